@@ -0,0 +1,150 @@
+/**
+ * Cache
+ */
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheFileName is the name of the build cache file
+const CacheFileName = ".swagger-gen-cache.json"
+
+// ParserVersion is the version of the parse results held by the cache
+// It must be bumped by every change that parses the same file content differently (tags, comments, models, FileResult),
+// so results cached by an older build are not reused
+const ParserVersion = 13
+
+// Cache holds the parse results of source files from a previous run
+// Results are reused when the path, content hash, swagger-gen and parser versions (and -discover flag) are unchanged
+type Cache struct {
+	Version string                `json:"version"`
+	Parser  int                   `json:"parser"`
+	Files   map[string]CacheEntry `json:"files"`
+}
+
+// CacheEntry is the parse result of a single file
+type CacheEntry struct {
+	Hash     string     `json:"hash"` // sha256 of the file's content
+	Discover bool       `json:"discover"`
+	Result   FileResult `json:"result"`
+}
+
+// NewCache returns an empty cache for the current version of swagger-gen
+func NewCache() *Cache {
+	return &Cache{
+		Version: Version,
+		Parser:  ParserVersion,
+		Files:   map[string]CacheEntry{},
+	}
+}
+
+// ReadCache reads the cache file at `cachePath`
+// A missing or unreadable cache file, or one written by another version of swagger-gen or of the parser, results in an empty cache
+func ReadCache(cachePath string) *Cache {
+
+	raw, err := ioutil.ReadFile(cachePath)
+	if err != nil {
+		return NewCache()
+	}
+
+	cache := &Cache{}
+	if err = json.Unmarshal(raw, cache); err != nil || cache.Version != Version || cache.Parser != ParserVersion || cache.Files == nil {
+		return NewCache()
+	}
+
+	return cache
+}
+
+// Lookup returns the cached result of a file, if its content and options are unchanged
+func (c *Cache) Lookup(filePath string, hash string, discover bool) (result FileResult, ok bool) {
+
+	if c == nil {
+		return
+	}
+
+	entry, ok := c.Files[filePath]
+	if !ok || entry.Hash != hash || entry.Discover != discover {
+		return result, false
+	}
+
+	return entry.Result, true
+}
+
+// Update replaces the entries of the cache with the results of the files parsed in this run
+func (c *Cache) Update(results []FileResult, discover bool) {
+
+	c.Version = Version
+	c.Parser = ParserVersion
+	c.Files = map[string]CacheEntry{}
+
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		c.Files[result.FilePath] = CacheEntry{
+			Hash:     result.Hash,
+			Discover: discover,
+			Result:   result,
+		}
+	}
+}
+
+// Write saves the cache to `cachePath`, creating the directory it is in
+func (c *Cache) Write(cachePath string) error {
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(cachePath, data)
+}
+
+// DefaultCachePath returns the path of the build cache of the project in `sourceDir`, in the user cache directory
+// (e.g. ~/.cache/swagger-gen on linux) so it is kept out of the project. Empty if there is no user cache directory
+func DefaultCachePath(sourceDir string) string {
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	absDir, err := filepath.Abs(sourceDir)
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(cacheDir, "swagger-gen", HashContent([]byte(absDir))[0:16], CacheFileName)
+}
+
+// HashContent returns the sha256 of a file's content
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// splitLines splits a file's content into lines the same way as readLines
+func splitLines(content []byte) []string {
+
+	if len(content) == 0 {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return lines
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+
+	rootPath, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	usersPath := filepath.Join(rootPath, "users.go")
+	ordersPath := filepath.Join(rootPath, "orders.go")
+	cachePath := filepath.Join(rootPath, CacheFileName)
+
+	ioutil.WriteFile(usersPath, []byte("// @route GetUsers GET /users\n// @return 200 []User\n// @x-internal\nfunc GetUsers() {}\n"), 0644)
+	ioutil.WriteFile(ordersPath, []byte("// @route GetOrders GET /orders\n// @return abc Order\nfunc GetOrders() {}\n"), 0644)
+
+	filePaths := []string{usersPath, ordersPath}
+
+	cache := ReadCache(cachePath)
+	parsed := ParseFiles(nil, filePaths, false, 2, cache)
+
+	if parsed[0].Cached || parsed[1].Cached {
+		t.Errorf("ParseFiles should not have used an empty cache")
+	}
+
+	cache.Update(parsed, false)
+
+	if err := cache.Write(cachePath); err != nil {
+		t.Fatalf("Cache.Write should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	ioutil.WriteFile(ordersPath, []byte("// @route GetOrders GET /orders\n// @return 200 Order\nfunc GetOrders() {}\n"), 0644)
+
+	reparsed := ParseFiles(nil, filePaths, false, 2, ReadCache(cachePath))
+
+	if !reparsed[0].Cached || reparsed[1].Cached {
+		t.Errorf("ParseFiles should have reused only the result of the unchanged file (actually %t, %t)", reparsed[0].Cached, reparsed[1].Cached)
+	}
+
+	reparsed[0].Cached = false
+	if !reflect.DeepEqual(parsed[0], reparsed[0]) {
+		t.Errorf("ParseFiles should have returned the same result from the cache (actually %+v)", reparsed[0])
+	}
+
+	if len(reparsed[1].Diagnostics) != 0 {
+		t.Errorf("ParseFiles should have re-parsed the changed file (actually %v)", reparsed[1].Diagnostics)
+	}
+
+	if _, ok := ReadCache(cachePath).Lookup(usersPath, parsed[0].Hash, true); ok {
+		t.Errorf("Cache.Lookup should not have returned a result parsed without -discover")
+	}
+
+	ioutil.WriteFile(cachePath, []byte(`{"version": "0.0.1", "files": {}}`), 0644)
+
+	if cache := ReadCache(cachePath); cache.Version != Version {
+		t.Errorf("ReadCache should have ignored a cache written by another version (actually %s)", cache.Version)
+	}
+
+	ioutil.WriteFile(cachePath, []byte(fmt.Sprintf(`{"version": "%s", "parser": %d, "files": {"%s": {}}}`, Version, ParserVersion-1, usersPath)), 0644)
+
+	if cache := ReadCache(cachePath); len(cache.Files) > 0 {
+		t.Errorf("ReadCache should have ignored a cache written by another version of the parser (actually %v)", cache.Files)
+	}
+}
+
+func TestDefaultCachePath(t *testing.T) {
+
+	if _, err := os.UserCacheDir(); err != nil {
+		t.Skip("no user cache directory")
+	}
+
+	cachePath := DefaultCachePath("api")
+
+	if len(cachePath) == 0 || filepath.Base(cachePath) != CacheFileName {
+		t.Errorf("DefaultCachePath should have returned a %s file (actually '%s')", CacheFileName, cachePath)
+	}
+
+	if DefaultCachePath("api") != cachePath || DefaultCachePath("models") == cachePath {
+		t.Errorf("DefaultCachePath should have returned a path per source directory")
+	}
+
+	if workDir, _ := os.Getwd(); DefaultCachePath(filepath.Join(workDir, "api")) != cachePath {
+		t.Errorf("DefaultCachePath should have returned the same path for a relative and an absolute source directory")
+	}
+}
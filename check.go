@@ -0,0 +1,250 @@
+/**
+ * Check
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// maxChangedFields is the number of changed fields listed for a changed path or definition
+const maxChangedFields = 3
+
+// specSections are the sections of the swagger spec (and OpenAPI document) whose entries are compared one by one, with the label of an entry
+var specSections = []struct {
+	Key   string
+	Label string
+}{
+	{"paths", ""},
+	{"definitions", "definition "},
+	{"parameters", "parameter "},
+	{"responses", "response "},
+	{"components/schemas", "schema "},
+	{"components/parameters", "parameter "},
+	{"components/responses", "response "},
+	{"components/securitySchemes", "security scheme "},
+}
+
+// CheckOutput compares the swagger spec (or OpenAPI document) with the one already in the output file, ignoring key order and formatting
+// It returns the changes regenerating the file would make (see DiffSpecs), which are empty if the file is up to date
+func CheckOutput(spec interface{}, output Output) ([]string, error) {
+
+	existingData, err := ioutil.ReadFile(output.Path)
+	if os.IsNotExist(err) {
+		return []string{"+ " + output.Path + " (the file does not exist)"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := DecodeSpec(existingData, output.Format)
+	if err != nil {
+		return nil, fmt.Errorf("The output %s could not be read: %s", output.Path, err.Error())
+	}
+
+	builtData, err := MarshalSwagger(spec, "json")
+	if err != nil {
+		return nil, err
+	}
+
+	built, err := DecodeSpec(builtData, "json")
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffSpecs(existing, built), nil
+}
+
+// DecodeSpec decodes a json or yaml swagger spec into maps, slices and json values
+// so specs in either format can be compared
+func DecodeSpec(data []byte, format string) (spec interface{}, err error) {
+
+	if format != "yaml" {
+		err = json.Unmarshal(data, &spec)
+		return
+	}
+
+	var document interface{}
+	if err = yaml.Unmarshal(data, &document); err != nil {
+		return
+	}
+
+	// yaml numbers and map keys are converted to their json equivalents by encoding the document as json
+	jsonData, err := json.Marshal(yamlToJSON(document))
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(jsonData, &spec)
+	return
+}
+
+// yamlToJSON converts the `map[interface{}]interface{}` maps of a yaml document to maps with string keys
+func yamlToJSON(value interface{}) interface{} {
+
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, item := range value {
+			converted[fmt.Sprint(key)] = yamlToJSON(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, item := range value {
+			converted[i] = yamlToJSON(item)
+		}
+		return converted
+	}
+
+	return value
+}
+
+// DiffSpecs lists the paths (by operation), definitions and other entries of the swagger spec `existing`
+// that are added (+), removed (-) or changed (~) in the swagger spec `built`
+// Examples: `+ POST /users`, `~ definition User (properties.email.format)`, `~ info`
+func DiffSpecs(existing interface{}, built interface{}) (changes []string) {
+
+	existingSpec, _ := existing.(map[string]interface{})
+	builtSpec, _ := built.(map[string]interface{})
+
+	for _, section := range specSections {
+
+		existingEntries := specSection(existingSpec, section.Key)
+		builtEntries := specSection(builtSpec, section.Key)
+
+		for _, name := range unionKeys(existingEntries, builtEntries) {
+
+			// Paths are compared by operation
+			if section.Key == "paths" {
+				existingOperations, isMap := existingEntries[name].(map[string]interface{})
+				builtOperations, isBuiltMap := builtEntries[name].(map[string]interface{})
+				if isMap || isBuiltMap {
+					for _, method := range unionKeys(existingOperations, builtOperations) {
+						existingOperation, inExisting := existingOperations[method]
+						builtOperation, inBuilt := builtOperations[method]
+						changes = append(changes, diffEntry(strings.ToUpper(method)+" "+name, existingOperation, inExisting, builtOperation, inBuilt)...)
+					}
+					continue
+				}
+			}
+
+			existingEntry, inExisting := existingEntries[name]
+			builtEntry, inBuilt := builtEntries[name]
+			changes = append(changes, diffEntry(section.Label+name, existingEntry, inExisting, builtEntry, inBuilt)...)
+		}
+	}
+
+	for _, key := range unionKeys(existingSpec, builtSpec) {
+
+		isSection := false
+		for _, section := range specSections {
+			isSection = isSection || strings.Split(section.Key, "/")[0] == key
+		}
+		if isSection {
+			continue
+		}
+
+		existingValue, inExisting := existingSpec[key]
+		builtValue, inBuilt := builtSpec[key]
+		changes = append(changes, diffEntry(key, existingValue, inExisting, builtValue, inBuilt)...)
+	}
+
+	return
+}
+
+// specSection returns the entries of a section of a decoded spec (e.g. `components/schemas`)
+func specSection(spec map[string]interface{}, key string) (entries map[string]interface{}) {
+
+	entries = spec
+	for _, part := range strings.Split(key, "/") {
+		entries, _ = entries[part].(map[string]interface{})
+	}
+
+	return
+}
+
+// diffEntry returns the change to an entry of the swagger spec, if any
+func diffEntry(label string, existing interface{}, inExisting bool, built interface{}, inBuilt bool) []string {
+
+	switch {
+	case !inExisting:
+		return []string{"+ " + label}
+	case !inBuilt:
+		return []string{"- " + label}
+	case reflect.DeepEqual(existing, built):
+		return nil
+	}
+
+	fields := changedFields(existing, built, "")
+	if len(fields) == 0 || (len(fields) == 1 && len(fields[0]) == 0) {
+		return []string{"~ " + label}
+	}
+
+	if len(fields) > maxChangedFields {
+		fields = append(fields[0:maxChangedFields], fmt.Sprintf("%d more", len(fields)-maxChangedFields))
+	}
+
+	return []string{"~ " + label + " (" + strings.Join(fields, ", ") + ")"}
+}
+
+// changedFields returns the dotted paths (e.g. `responses.200.schema`) of the values that differ between two json values
+func changedFields(existing interface{}, built interface{}, prefix string) (fields []string) {
+
+	if reflect.DeepEqual(existing, built) {
+		return nil
+	}
+
+	field := func(key string) string {
+		if len(prefix) == 0 {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	existingMap, isMap := existing.(map[string]interface{})
+	builtMap, isBuiltMap := built.(map[string]interface{})
+	if isMap && isBuiltMap {
+		for _, key := range unionKeys(existingMap, builtMap) {
+			fields = append(fields, changedFields(existingMap[key], builtMap[key], field(key))...)
+		}
+		return
+	}
+
+	existingList, isList := existing.([]interface{})
+	builtList, isBuiltList := built.([]interface{})
+	if isList && isBuiltList && len(existingList) == len(builtList) {
+		for i := range existingList {
+			fields = append(fields, changedFields(existingList[i], builtList[i], field(fmt.Sprint(i)))...)
+		}
+		return
+	}
+
+	return []string{prefix}
+}
+
+// unionKeys returns the keys of both maps, sorted
+func unionKeys(a map[string]interface{}, b map[string]interface{}) (keys []string) {
+
+	for key := range a {
+		keys = append(keys, key)
+	}
+
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSpec(t *testing.T) {
+
+	jsonSpec, jsonErr := DecodeSpec([]byte(`{"swagger": "2.0", "paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}, "x-rank": 1}`), "json")
+	yamlSpec, yamlErr := DecodeSpec([]byte("x-rank: 1\npaths:\n  /users:\n    get:\n      responses:\n        200:\n          description: OK\nswagger: \"2.0\"\n"), "yaml")
+
+	if jsonErr != nil || yamlErr != nil {
+		t.Fatalf("DecodeSpec should have returned a nil error (actually %v, %v)", jsonErr, yamlErr)
+	}
+
+	if !reflect.DeepEqual(jsonSpec, yamlSpec) {
+		t.Errorf("DecodeSpec should have decoded the same spec from json and yaml (actually %v and %v)", jsonSpec, yamlSpec)
+	}
+
+	if _, err := DecodeSpec([]byte(`{"swagger": `), "json"); err == nil {
+		t.Errorf("DecodeSpec should have returned an error for invalid json")
+	}
+}
+
+func TestDiffSpecs(t *testing.T) {
+
+	existing, _ := DecodeSpec([]byte(`{
+		"info": {"title": "API", "version": "1.0"},
+		"paths": {
+			"/users": {"get": {"summary": "List users"}, "delete": {"summary": "Delete users"}},
+			"/old": {"get": {}}
+		},
+		"definitions": {
+			"User": {"properties": {"email": {"type": "string"}, "name": {"type": "string"}}},
+			"Old": {}
+		}
+	}`), "json")
+
+	built, _ := DecodeSpec([]byte(`{
+		"info": {"version": "1.0", "title": "API"},
+		"paths": {
+			"/users": {"get": {"summary": "List all users"}, "post": {}, "delete": {"summary": "Delete users"}}
+		},
+		"definitions": {
+			"User": {"properties": {"name": {"type": "string"}, "email": {"type": "string", "format": "email"}}},
+			"Group": {}
+		},
+		"host": "example.com"
+	}`), "json")
+
+	expected := []string{
+		"- GET /old",
+		"~ GET /users (summary)",
+		"+ POST /users",
+		"+ definition Group",
+		"- definition Old",
+		"~ definition User (properties.email.format)",
+		"+ host",
+	}
+
+	if changes := DiffSpecs(existing, built); strings.Join(changes, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DiffSpecs should have returned:\n%s\n(actually:\n%s)", strings.Join(expected, "\n"), strings.Join(changes, "\n"))
+	}
+
+	if changes := DiffSpecs(existing, existing); len(changes) > 0 {
+		t.Errorf("DiffSpecs should have returned no changes for the same spec (actually %v)", changes)
+	}
+}
+
+func TestCheckOutput(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	swagger := Swagger{Swagger: "2.0", Paths: map[string]map[string]Path{"/users": {"get": Path{Summary: "List users"}}}}
+	outputs := []Output{{filepath.Join(dir, "swagger.json"), "json"}, {filepath.Join(dir, "swagger.yaml"), "yaml"}}
+
+	for _, output := range outputs {
+		if changes, err := CheckOutput(swagger, output); err != nil || len(changes) != 1 {
+			t.Errorf("CheckOutput should have returned 1 change for the missing file %s (actually %v, %v)", output.Path, changes, err)
+		}
+	}
+
+	if err = WriteOutputs(swagger, outputs); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, output := range outputs {
+		if changes, err := CheckOutput(swagger, output); err != nil || len(changes) > 0 {
+			t.Errorf("CheckOutput should have returned no changes for %s (actually %v, %v)", output.Path, changes, err)
+		}
+	}
+
+	swagger.Paths["/users"]["get"] = Path{Summary: "List all users"}
+
+	for _, output := range outputs {
+		if changes, err := CheckOutput(swagger, output); err != nil || strings.Join(changes, ",") != "~ GET /users (summary)" {
+			t.Errorf("CheckOutput should have returned '~ GET /users (summary)' for %s (actually %v, %v)", output.Path, changes, err)
+		}
+	}
+}
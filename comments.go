@@ -0,0 +1,160 @@
+/**
+ * Comments
+ */
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CommentLine is the comment text of a single source line
+type CommentLine struct {
+	Text      string
+	IsComment bool // false for code and blank lines
+	Skip      bool // the line is part of a comment but has no text (e.g. `/**`, `*/` or a `//go:generate` directive)
+}
+
+// directivePattern finds tool directives (e.g. `//go:generate`, `//nolint:errcheck`), which are not documentation
+var directivePattern = regexp.MustCompile(`^//(line |extern |export |[a-z0-9]+:[a-z0-9])`)
+
+// CommentSyntax describes how comments are written in a language
+type CommentSyntax struct {
+	LineComments []string // e.g. `//`, `#`
+	BlockStart   string   // e.g. `/*`, `"""` (no block comments if empty)
+	BlockEnd     string   // e.g. `*/`, `"""`
+}
+
+// ExtractComments returns the comment text of every line of a go source file, so the result has the same line numbers
+// The following forms are recognized, at any indentation:
+//
+//	// @route GetFoo GET /foo
+//	//@route GetFoo GET /foo
+//	/* @route GetFoo GET /foo */
+//	/**
+//	 * @route GetFoo GET /foo
+//	 */
+func ExtractComments(lines []string) (comments []CommentLine) {
+	return extractComments(lines, GoLanguage{}.CommentSyntax())
+}
+
+// extractComments returns the comment text of every line of a source file written with the comment `syntax`
+func extractComments(lines []string, syntax CommentSyntax) (comments []CommentLine) {
+
+	comments = make([]CommentLine, len(lines))
+
+	for lineNum := 0; lineNum < len(lines); lineNum++ {
+
+		line := strings.TrimSpace(lines[lineNum])
+
+		if len(syntax.BlockStart) > 0 && strings.HasPrefix(line, syntax.BlockStart) {
+			lineNum = blockComment(lines, lineNum, syntax, comments)
+			continue
+		}
+
+		for _, prefix := range syntax.LineComments {
+			if strings.HasPrefix(line, prefix) {
+				comments[lineNum] = lineComment(line, prefix)
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// lineComment returns the text of a comment line starting with `prefix`
+func lineComment(line string, prefix string) CommentLine {
+
+	// PHP attributes (e.g. `#[Route('/foo')]`) are code
+	if prefix == "#" && strings.HasPrefix(line, "#[") {
+		return CommentLine{}
+	}
+
+	if directivePattern.MatchString(line) {
+		return CommentLine{IsComment: true, Skip: true}
+	}
+
+	return CommentLine{
+		Text:      strings.TrimPrefix(line[len(prefix):], " "),
+		IsComment: true,
+	}
+}
+
+// blockComment sets the text of the lines of the block comment starting on line `startLine`
+// Returns the line the block comment ends on
+func blockComment(lines []string, startLine int, syntax CommentSyntax, comments []CommentLine) (endLine int) {
+
+	// The opening line is treated as a line inside the block, without its `/*` (or `/**`)
+	opening := strings.TrimSpace(lines[startLine])
+	opening = opening[len(syntax.BlockStart):]
+	if syntax.BlockStart == "/*" {
+		opening = strings.TrimLeft(opening, "*")
+	}
+
+	innerLines := []string{opening}
+
+	for endLine = startLine; endLine < len(lines); endLine++ {
+
+		if endLine > startLine {
+			innerLines = append(innerLines, lines[endLine])
+		}
+
+		last := innerLines[len(innerLines)-1]
+		if closeIdx := strings.Index(last, syntax.BlockEnd); closeIdx > -1 {
+			// Code after the end of the block (e.g. `/* comment */ func Foo()`) makes the line code
+			if len(strings.TrimSpace(last[closeIdx+len(syntax.BlockEnd):])) > 0 {
+				if endLine == startLine {
+					return
+				}
+			}
+			innerLines[len(innerLines)-1] = last[0:closeIdx]
+			break
+		}
+	}
+
+	if endLine == len(lines) {
+		endLine = len(lines) - 1
+	}
+
+	// Docblocks prefix every line with ` * `
+	isDocblock := syntax.BlockStart == "/*"
+	for i, innerLine := range innerLines {
+		trimmed := strings.TrimSpace(innerLine)
+		if i > 0 && len(trimmed) > 0 && !strings.HasPrefix(trimmed, "*") {
+			isDocblock = false
+		}
+	}
+
+	texts := make([]string, len(innerLines))
+	for i, innerLine := range innerLines {
+		if i == 0 {
+			texts[i] = strings.TrimSpace(innerLine)
+			continue
+		}
+		if isDocblock {
+			text := strings.TrimPrefix(strings.TrimSpace(innerLine), "*")
+			texts[i] = strings.TrimRight(strings.TrimPrefix(text, " "), " \t")
+			continue
+		}
+		texts[i] = strings.TrimRight(innerLine, " \t")
+	}
+
+	// Remove the indentation that the lines inside a plain block comment have in common
+	if !isDocblock && len(texts) > 1 {
+		for i, text := range strings.Split(dedent(texts[1:]), "\n") {
+			texts[i+1] = text
+		}
+	}
+
+	for i, text := range texts {
+		isEdge := (i == 0 || i == len(texts)-1) && len(strings.TrimSpace(text)) == 0
+		comments[startLine+i] = CommentLine{
+			Text:      text,
+			IsComment: true,
+			Skip:      isEdge,
+		}
+	}
+
+	return
+}
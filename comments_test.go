@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractComments(t *testing.T) {
+
+	lines := []string{
+		"package main",
+		"//go:generate swagger-gen",
+		"//@route GetFoo GET /foo",
+		"func GetFoo() {",
+		"	// @route GetBar GET /bar",
+		"	x := 1 // not a comment line",
+		"}",
+		"/**",
+		" * Gets a baz",
+		" *",
+		" * @param id int in:path",
+		" *   The id of the baz",
+		" */",
+		"/* @model Baz */",
+		"/*",
+		"    @route GetQux GET /qux",
+		"      The qux",
+		"*/",
+		"/* ignored */ func Quux() {}",
+	}
+
+	expected := []struct {
+		text      string
+		isComment bool
+		skip      bool
+	}{
+		{"", false, false},
+		{"", true, true},
+		{"@route GetFoo GET /foo", true, false},
+		{"", false, false},
+		{"@route GetBar GET /bar", true, false},
+		{"", false, false},
+		{"", false, false},
+		{"", true, true},
+		{"Gets a baz", true, false},
+		{"", true, false},
+		{"@param id int in:path", true, false},
+		{"  The id of the baz", true, false},
+		{"", true, true},
+		{"@model Baz", true, false},
+		{"", true, true},
+		{"@route GetQux GET /qux", true, false},
+		{"  The qux", true, false},
+		{"", true, true},
+		{"", false, false},
+	}
+
+	comments := ExtractComments(lines)
+
+	if len(comments) != len(lines) {
+		t.Fatalf("ExtractComments should have returned %d lines (actually %d)", len(lines), len(comments))
+	}
+
+	for i, comment := range comments {
+		if comment.Text != expected[i].text || comment.IsComment != expected[i].isComment || comment.Skip != expected[i].skip {
+			t.Errorf("ExtractComments returned an unexpected comment for line %d '%s' (%+v)", i, lines[i], comment)
+		}
+	}
+}
+
+func TestGetCommentBlock_Docblock(t *testing.T) {
+
+	lines := []string{
+		"/**",
+		" * Gets a foo",
+		" * @route GetFoo GET /foo",
+		" */",
+		"func GetFoo() {}",
+	}
+
+	comments, blockStart, blockEnd := GetCommentBlock(lines, 2)
+
+	if strings.Join(comments, "|") != "Gets a foo|@route GetFoo GET /foo" || blockStart != 0 || blockEnd != 3 {
+		t.Errorf("GetCommentBlock should have returned 2 comments from line 0 to 3 (actually %v from line %d to %d)", comments, blockStart, blockEnd)
+	}
+
+	routes, _ := GetRoutes(lines, "foo.go")
+
+	if len(routes["/foo"]) != 1 || routes["/foo"][0].Handler != "GetFoo" || routes["/foo"][0].Description != "Gets a foo" {
+		t.Errorf("GetRoutes should have returned the GetFoo route of a docblock (actually %+v)", routes)
+	}
+}
@@ -0,0 +1,80 @@
+/**
+ * Descriptions
+ */
+package main
+
+import (
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+)
+
+// IsDescriptionFile checks if a description references a markdown file (e.g. `file:docs/api/create-order.md`)
+func IsDescriptionFile(description string) bool {
+	return strings.HasPrefix(description, TagArgFilePrefix)
+}
+
+// ResolveReferencedFile finds a file referenced by a tag (e.g. `file:docs/api/create-order.md`) relative to the
+// source file that references it, falling back to the root of the project
+// Files are looked for in `fsys` (the OS filesystem if nil), where the sources are read from
+func ResolveReferencedFile(fsys fs.FS, referencedPath string, filePath string, rootPath string) (string, bool) {
+
+	if fsys == nil {
+		fsys = osFS{}
+	}
+
+	candidates := []string{referencedPath}
+
+	if !path.IsAbs(referencedPath) {
+		candidates = []string{
+			path.Join(path.Dir(filePath), referencedPath),
+			path.Join(rootPath, referencedPath),
+		}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := fs.Stat(fsys, candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// resolveReferencedFile resolves a file referenced by the source file `filePath` (see ResolveReferencedFile),
+// adding it to the referenced files of the build
+func (s *Swaggerf) resolveReferencedFile(referencedPath string, filePath string) (resolvedPath string, ok bool) {
+
+	if resolvedPath, ok = ResolveReferencedFile(s.FS, referencedPath, filePath, s.rootPath); ok && !inArray(resolvedPath, s.referencedFiles) {
+		s.referencedFiles = append(s.referencedFiles, resolvedPath)
+	}
+
+	return
+}
+
+// loadDescription returns `description`, or the contents of the markdown file it references
+// A missing file is reported as a lint warning and results in an empty description
+func (s *Swaggerf) loadDescription(description string, filePath string, lineNum int) string {
+
+	if !IsDescriptionFile(description) {
+		return description
+	}
+
+	descriptionPath := strings.TrimSpace(description[len(TagArgFilePrefix):])
+	resolvedPath, ok := s.resolveReferencedFile(descriptionPath, filePath)
+
+	if !ok {
+		log.Printf("Lint Warning: description file %s not found (File: %s; Line: %d)", descriptionPath, filePath, lineNum)
+		return ""
+	}
+
+	contents, err := fs.ReadFile(s.sourceFS(), resolvedPath)
+
+	if err != nil {
+		log.Printf("Lint Warning: description file %s could not be read: %s (File: %s; Line: %d)", resolvedPath, err.Error(), filePath, lineNum)
+		return ""
+	}
+
+	return strings.TrimSpace(string(contents))
+}
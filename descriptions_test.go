@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadDescription(t *testing.T) {
+
+	rootPath, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	os.MkdirAll(filepath.Join(rootPath, "api"), 0755)
+	os.MkdirAll(filepath.Join(rootPath, "docs"), 0755)
+	ioutil.WriteFile(filepath.Join(rootPath, "api", "orders.md"), []byte("Relative to the source file\n"), 0644)
+	ioutil.WriteFile(filepath.Join(rootPath, "docs", "orders.md"), []byte("Relative to the root\n"), 0644)
+
+	s := Swaggerf{rootPath: rootPath}
+	filePath := filepath.Join(rootPath, "api", "orders.go")
+
+	if description := s.loadDescription("file:orders.md", filePath, 0); description != "Relative to the source file" {
+		t.Errorf("loadDescription should have returned '%s' (actually '%s')", "Relative to the source file", description)
+	}
+
+	if description := s.loadDescription("file:docs/orders.md", filePath, 0); description != "Relative to the root" {
+		t.Errorf("loadDescription should have returned '%s' (actually '%s')", "Relative to the root", description)
+	}
+
+	if description := s.loadDescription("file:missing.md", filePath, 0); description != "" {
+		t.Errorf("loadDescription should have returned an empty description (actually '%s')", description)
+	}
+
+	if description := s.loadDescription("Inline description", filePath, 0); description != "Inline description" {
+		t.Errorf("loadDescription should have returned '%s' (actually '%s')", "Inline description", description)
+	}
+
+	if len(s.referencedFiles) != 2 {
+		t.Errorf("loadDescription should have added the 2 description files to the referenced files (actually %v)", s.referencedFiles)
+	}
+}
+
+func TestResolveReferencedFile(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"api/orders.md":  {Data: []byte("Relative to the source file")},
+		"docs/orders.md": {Data: []byte("Relative to the root")},
+	}
+
+	if resolvedPath, ok := ResolveReferencedFile(fsys, "orders.md", "api/orders.go", "."); !ok || resolvedPath != "api/orders.md" {
+		t.Errorf("ResolveReferencedFile should have returned '%s' (actually '%s')", "api/orders.md", resolvedPath)
+	}
+
+	if resolvedPath, ok := ResolveReferencedFile(fsys, "docs/orders.md", "api/orders.go", "."); !ok || resolvedPath != "docs/orders.md" {
+		t.Errorf("ResolveReferencedFile should have returned '%s' (actually '%s')", "docs/orders.md", resolvedPath)
+	}
+
+	if _, ok := ResolveReferencedFile(fsys, "/docs/orders.md", "api/orders.go", "."); ok {
+		t.Errorf("ResolveReferencedFile should not have resolved an absolute path outside of the filesystem")
+	}
+}
+
+func TestBuildSwagger_ModelDescriptionFile(t *testing.T) {
+
+	rootPath, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	os.MkdirAll(filepath.Join(rootPath, "models"), 0755)
+	ioutil.WriteFile(filepath.Join(rootPath, "models", "user.md"), []byte("A user of the system\n"), 0644)
+	ioutil.WriteFile(filepath.Join(rootPath, "models", "user.go"), []byte("package models\n\n// @model User\n// @description file:user.md\ntype User struct {\n\tName string\n}\n"), 0644)
+
+	lines, _ := readLines(filepath.Join(rootPath, "models", "user.go"))
+	models, _ := GetModels(lines, filepath.Join(rootPath, "models", "user.go"))
+	if model := models["User"]; model.FilePath != filepath.Join(rootPath, "models", "user.go") || model.LineNum != 4 {
+		t.Errorf("GetModels should have set the file path and line of the model (actually %s, %d)", model.FilePath, model.LineNum)
+	}
+
+	s := Swaggerf{}
+	if err = s.BuildSwagger(rootPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if description := s.Swagger.Definitions["User"].Description; description != "A user of the system" {
+		t.Errorf("BuildSwagger should have read the description of the model from the file next to it (actually '%s')", description)
+	}
+}
@@ -0,0 +1,447 @@
+/**
+ * Examples
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ParseRouteExample parses a route's example tag (@example)
+// The target is `body` or a response code, followed by inline JSON or a JSON file
+// Examples: @example 200 file:testdata/user.json, @example body {"name": "Jane"}
+func ParseRouteExample(ret string) (example RouteExample, err error) {
+
+	retParts := strings.Fields(ret)
+
+	if len(retParts) < 2 {
+		err = fmt.Errorf("The tag @example is not in the correct format: '%s'", ret)
+		return
+	}
+
+	example.Target = retParts[0]
+
+	if example.Target != TagArgExampleBody {
+		if _, _, err = ParseResponseKey(example.Target); err != nil {
+			return
+		}
+	}
+
+	example.Value = strings.TrimSpace(strings.TrimSpace(ret)[len(example.Target):])
+
+	return
+}
+
+// loadExample parses the JSON of an example, loading it from a file if it references one
+func (s *Swaggerf) loadExample(example RouteExample, filePath string, lineNum int) (value interface{}, ok bool) {
+
+	raw := []byte(example.Value)
+
+	if strings.HasPrefix(example.Value, TagArgFilePrefix) {
+
+		examplePath := strings.TrimSpace(example.Value[len(TagArgFilePrefix):])
+		resolvedPath, found := s.resolveReferencedFile(examplePath, filePath)
+
+		if !found {
+			log.Printf("Example Error: example file %s not found (File: %s; Line: %d)", examplePath, filePath, lineNum)
+			return
+		}
+
+		var err error
+		if raw, err = fs.ReadFile(s.sourceFS(), resolvedPath); err != nil {
+			log.Printf("Example Error: example file %s could not be read: %s (File: %s; Line: %d)", resolvedPath, err.Error(), filePath, lineNum)
+			return
+		}
+	}
+
+	if err := json.Unmarshal(raw, &value); err != nil {
+		log.Printf("Example Error: @example %s is not valid JSON: %s (File: %s; Line: %d)", example.Target, err.Error(), filePath, lineNum)
+		return
+	}
+
+	ok = true
+	return
+}
+
+// addExamples adds the examples of a route to the body param and responses of its operation,
+// reporting examples that do not match their model
+func (s *Swaggerf) addExamples(route Route, path *Path) {
+
+	for _, example := range route.Examples {
+
+		value, ok := s.loadExample(example, route.FilePath, route.LineNum)
+		if !ok {
+			continue
+		}
+
+		schemaRef := ""
+		found := false
+
+		if example.Target == TagArgExampleBody {
+
+			for i, parameter := range path.Parameters {
+				if parameter.In != TransportBody || len(parameter.Ref) > 0 {
+					continue
+				}
+				path.Parameters[i].XExample = value
+				found = true
+			}
+
+			for _, param := range route.Params {
+				if param.In == TransportBody {
+					schemaRef = param.Type
+				}
+			}
+		} else {
+
+			if response, ok := path.Responses[example.Target]; ok && len(response.Ref) == 0 {
+				if response.Examples == nil {
+					response.Examples = map[string]interface{}{}
+				}
+				response.Examples[exampleMimeType(path, s.Swagger.Produces)] = value
+				path.Responses[example.Target] = response
+				found = true
+			}
+
+			for _, response := range route.Responses {
+				if response.Key() == example.Target {
+					schemaRef = response.SchemaRef
+				}
+			}
+		}
+
+		if !found {
+			log.Printf("Example Error: @example %s of @route %s has no matching %s (File: %s; Line: %d)", example.Target, route.OperationID, exampleTargetName(example.Target), route.FilePath, route.LineNum)
+			continue
+		}
+
+		for _, problem := range ValidateExample(value, schemaRef, s.Swagger.Definitions) {
+			log.Printf("Example Error: @example %s of @route %s is stale: %s (File: %s; Line: %d)", example.Target, route.OperationID, problem, route.FilePath, route.LineNum)
+		}
+	}
+}
+
+// exampleTargetName describes the target of an example for log messages
+func exampleTargetName(target string) string {
+	if target == TagArgExampleBody {
+		return "body param"
+	}
+	return "response"
+}
+
+// exampleMimeType returns the mime type response examples of an operation are keyed by
+func exampleMimeType(path *Path, globalProduces []string) string {
+
+	if len(path.Produces) > 0 {
+		return path.Produces[0]
+	}
+
+	if len(globalProduces) > 0 {
+		return globalProduces[0]
+	}
+
+	return MimeTypeJSON
+}
+
+// ValidateExample checks that an example value matches the model (or `[]model`, or primitive type) `schemaRef`
+// Returns a description of every mismatch, prefixed with its JSON path
+func ValidateExample(value interface{}, schemaRef string, definitions map[string]ModelDefinition) (problems []string) {
+
+	if len(schemaRef) == 0 || schemaRef == "empty" {
+		return
+	}
+
+	property := Property{}
+
+	if strings.HasPrefix(schemaRef, "[]") {
+		property.Type = SwaggerTypeArray
+		property.Items = map[string]string{}
+		if _, ok := definitions[schemaRef[2:]]; ok {
+			property.Items["$ref"] = "#/definitions/" + schemaRef[2:]
+		} else {
+			property.Items["type"] = swaggerType(schemaRef[2:])
+		}
+	} else if _, ok := definitions[schemaRef]; ok {
+		property.Ref = "#/definitions/" + schemaRef
+	} else {
+		property.Type = swaggerType(schemaRef)
+	}
+
+	return validateExampleProperty(value, property, definitions, "$")
+}
+
+// validateExampleProperty checks that `value` matches `property`
+func validateExampleProperty(value interface{}, property Property, definitions map[string]ModelDefinition, jsonPath string) (problems []string) {
+
+	if value == nil {
+		return
+	}
+
+	if len(property.Ref) > 0 {
+		name := strings.TrimPrefix(property.Ref, "#/definitions/")
+		definition, ok := definitions[name]
+		if !ok {
+			return
+		}
+		return validateExampleDefinition(value, definition, definitions, jsonPath)
+	}
+
+	switch property.Type {
+	case SwaggerTypeArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected array, got %s", jsonPath, jsonTypeName(value))}
+		}
+		itemProperty := Property{
+			Type: property.Items["type"],
+			Ref:  property.Items["$ref"],
+		}
+		for i, item := range items {
+			problems = append(problems, validateExampleProperty(item, itemProperty, definitions, fmt.Sprintf("%s[%d]", jsonPath, i))...)
+		}
+	case SwaggerTypeString:
+		if _, ok := value.(string); !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected string, got %s", jsonPath, jsonTypeName(value)))
+		}
+	case SwaggerTypeBool:
+		if _, ok := value.(bool); !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected boolean, got %s", jsonPath, jsonTypeName(value)))
+		}
+	case SwaggerTypeFloat:
+		if _, ok := value.(float64); !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected number, got %s", jsonPath, jsonTypeName(value)))
+		}
+	case SwaggerTypeInt:
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) {
+			problems = append(problems, fmt.Sprintf("%s: expected integer, got %s", jsonPath, jsonTypeName(value)))
+		}
+	}
+
+	return
+}
+
+// validateExampleDefinition checks that `value` is an object with the properties of `definition`
+func validateExampleDefinition(value interface{}, definition ModelDefinition, definitions map[string]ModelDefinition, jsonPath string) (problems []string) {
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected object, got %s", jsonPath, jsonTypeName(value))}
+	}
+
+	keys := []string{}
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		property, ok := definition.Properties[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s.%s: unknown property", jsonPath, key))
+			continue
+		}
+		problems = append(problems, validateExampleProperty(object[key], property, definitions, jsonPath+"."+key)...)
+	}
+
+	for _, name := range definition.Required {
+		if _, ok := object[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s.%s: missing required property", jsonPath, name))
+		}
+	}
+
+	return
+}
+
+// jsonTypeName returns the JSON type of a decoded JSON value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+// generateExamples attaches a generated example to every definition and response that does not have one
+func (s *Swaggerf) generateExamples() {
+
+	names := []string{}
+	for name := range s.Swagger.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Examples are generated from the definitions as written (so hand-written `@example`s are reused) and assigned afterwards
+	generated := map[string]interface{}{}
+	for _, name := range names {
+		if s.Swagger.Definitions[name].Example == nil {
+			generated[name] = GenerateExample(Property{Ref: "#/definitions/" + name}, s.Swagger.Definitions)
+		}
+	}
+
+	for name, example := range generated {
+		definition := s.Swagger.Definitions[name]
+		definition.Example = example
+		s.Swagger.Definitions[name] = definition
+	}
+
+	for _, operations := range s.Swagger.Paths {
+		for verb, path := range operations {
+			for key, response := range path.Responses {
+
+				if len(response.Ref) > 0 || len(response.Examples) > 0 {
+					continue
+				}
+
+				example := GenerateExample(Property{
+					Type:  response.Schema.Type,
+					Ref:   response.Schema.Ref,
+					Items: response.Schema.Items,
+				}, s.Swagger.Definitions)
+
+				if example == nil {
+					continue
+				}
+
+				response.Examples = map[string]interface{}{
+					exampleMimeType(&path, s.Swagger.Produces): example,
+				}
+				path.Responses[key] = response
+			}
+			operations[verb] = path
+		}
+	}
+}
+
+// GenerateExample synthesizes a deterministic example value for `property`
+// Field level `@example`s, enums, defaults and bounds are respected, and definitions are expanded recursively
+// Returns nil if no example can be generated (e.g. for a file, or a definition that references itself)
+func GenerateExample(property Property, definitions map[string]ModelDefinition) interface{} {
+	return generateExample(property, definitions, map[string]bool{})
+}
+
+// generateExample synthesizes an example for `property`, skipping the definitions in `visiting` to break reference cycles
+func generateExample(property Property, definitions map[string]ModelDefinition, visiting map[string]bool) interface{} {
+
+	if property.Example != nil {
+		return property.Example
+	}
+
+	if len(property.Ref) > 0 {
+
+		name := strings.TrimPrefix(property.Ref, "#/definitions/")
+		definition, ok := definitions[name]
+		if !ok || visiting[name] {
+			return nil
+		}
+
+		if definition.Example != nil {
+			return definition.Example
+		}
+
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		object := map[string]interface{}{}
+		for key, child := range definition.Properties {
+			if value := generateExample(child, definitions, visiting); value != nil {
+				object[key] = value
+			}
+		}
+
+		return object
+	}
+
+	if len(property.Enum) > 0 {
+		if property.Type == SwaggerTypeArray {
+			return []interface{}{property.Enum[0]}
+		}
+		return property.Enum[0]
+	}
+
+	if property.Default != nil {
+		return property.Default
+	}
+
+	switch property.Type {
+	case SwaggerTypeArray:
+		item := generateExample(Property{
+			Type:   property.Items["type"],
+			Format: property.Items["format"],
+			Ref:    property.Items["$ref"],
+		}, definitions, visiting)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case SwaggerTypeString:
+		return exampleString(property.Format)
+	case SwaggerTypeBool:
+		return true
+	case SwaggerTypeInt:
+		if number := exampleNumber(property); number < 0 {
+			return int64(math.Floor(number))
+		} else {
+			return int64(math.Ceil(number))
+		}
+	case SwaggerTypeFloat:
+		return exampleNumber(property)
+	}
+
+	return nil
+}
+
+// exampleNumber returns 0, moved inside the bounds of `property` when they exclude it
+func exampleNumber(property Property) float64 {
+
+	if property.Minimum != nil && *property.Minimum > 0 {
+		return *property.Minimum
+	}
+
+	if property.Maximum != nil && *property.Maximum < 0 {
+		return *property.Maximum
+	}
+
+	return 0
+}
+
+// exampleString returns an example string for a string format
+func exampleString(format string) string {
+	switch format {
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte":
+		return "ZXhhbXBsZQ=="
+	case "password":
+		return "********"
+	}
+	return "string"
+}
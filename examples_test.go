@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRouteExample(t *testing.T) {
+
+	example, err := ParseRouteExample("200 file:testdata/user.json")
+
+	if err != nil {
+		t.Errorf("ParseRouteExample should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if example.Target != "200" || example.Value != "file:testdata/user.json" {
+		t.Errorf("ParseRouteExample should have returned 200 file:testdata/user.json (actually %s %s)", example.Target, example.Value)
+	}
+
+	example, _ = ParseRouteExample("body {\"name\": \"Jane\",\n\"age\": 30}")
+
+	if example.Target != "body" || example.Value != "{\"name\": \"Jane\",\n\"age\": 30}" {
+		t.Errorf("ParseRouteExample returned an unexpected body example (%s %s)", example.Target, example.Value)
+	}
+
+	if _, err := ParseRouteExample("ok {}"); err == nil {
+		t.Errorf("ParseRouteExample should have returned an error (actually nil)")
+	}
+}
+
+func TestValidateExample(t *testing.T) {
+
+	definitions := map[string]ModelDefinition{
+		"User": {
+			Type:     "object",
+			Required: []string{"email"},
+			Properties: map[string]Property{
+				"name":  {Type: "string"},
+				"email": {Type: "string"},
+				"age":   {Type: "integer"},
+				"tags":  {Type: "array", Items: map[string]string{"type": "string"}},
+				"group": {Ref: "#/definitions/Group"},
+			},
+		},
+		"Group": {
+			Type: "object",
+			Properties: map[string]Property{
+				"id": {Type: "integer"},
+			},
+		},
+	}
+
+	var value interface{}
+	json.Unmarshal([]byte(`{"email": "jane@example.com", "name": "Jane", "age": 30, "tags": ["a"], "group": {"id": 1}}`), &value)
+
+	if problems := ValidateExample(value, "User", definitions); len(problems) != 0 {
+		t.Errorf("ValidateExample should not have returned any problems (actually %v)", problems)
+	}
+
+	json.Unmarshal([]byte(`[{"name": 1, "age": 1.5, "tags": [2], "group": {"id": "1"}, "nickname": "J"}]`), &value)
+
+	expected := []string{
+		"$[0].age: expected integer, got number",
+		"$[0].group.id: expected integer, got string",
+		"$[0].name: expected string, got number",
+		"$[0].nickname: unknown property",
+		"$[0].tags[0]: expected string, got number",
+		"$[0].email: missing required property",
+	}
+
+	problems := ValidateExample(value, "[]User", definitions)
+
+	if len(problems) != len(expected) {
+		t.Fatalf("ValidateExample should have returned %d problems (actually %v)", len(expected), problems)
+	}
+
+	for i, problem := range expected {
+		if problems[i] != problem {
+			t.Errorf("ValidateExample should have returned '%s' (actually '%s')", problem, problems[i])
+		}
+	}
+}
+
+func TestGenerateExample(t *testing.T) {
+
+	one := 1.0
+	definitions := map[string]ModelDefinition{
+		"User": {
+			Type: "object",
+			Properties: map[string]Property{
+				"id":      {Type: "integer", Minimum: &one},
+				"email":   {Type: "string", Format: "email"},
+				"name":    {Type: "string", Example: "Jane"},
+				"status":  {Type: "string", Enum: []interface{}{"active", "disabled"}},
+				"admin":   {Type: "boolean"},
+				"groups":  {Type: "array", Items: map[string]string{"$ref": "#/definitions/Group"}},
+				"manager": {Ref: "#/definitions/User"},
+			},
+		},
+		"Group": {
+			Type:       "object",
+			Properties: map[string]Property{"id": {Type: "integer"}},
+			Example:    map[string]interface{}{"id": 7},
+		},
+	}
+
+	example, _ := json.Marshal(GenerateExample(Property{Ref: "#/definitions/User"}, definitions))
+	expected := `{"admin":true,"email":"user@example.com","groups":[{"id":7}],"id":1,"name":"Jane","status":"active"}`
+
+	if string(example) != expected {
+		t.Errorf("GenerateExample should have returned %s (actually %s)", expected, string(example))
+	}
+
+	if example := GenerateExample(Property{Type: "file"}, definitions); example != nil {
+		t.Errorf("GenerateExample should have returned nil for a file (actually %v)", example)
+	}
+}
+
+func TestBuildSwagger_ExampleNames(t *testing.T) {
+
+	fsys := NewMemFS()
+	for name, content := range map[string]string{
+		"models/user.go": "package models\n\n// @model User\ntype User struct {\n\tUserID int `json:\"user_id\" binding:\"required\"`\n}\n",
+		"api/users.go":   "package api\n\n// @route GetUser GET /user\n// @return 200 User\n// @example 200 {\"UserID\": 1}\nfunc GetUser() {}\n",
+	} {
+		content := []byte(content)
+		fsys.AddFile(name, int64(len(content)), time.Time{}, func() ([]byte, error) {
+			return content, nil
+		})
+	}
+
+	logs := bytes.Buffer{}
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	s := Swaggerf{FS: fsys, GenerateExamples: true}
+	if err := s.BuildSwagger("."); err != nil {
+		t.Fatal(err)
+	}
+
+	definition := s.Swagger.Definitions["User"]
+	if _, ok := definition.Properties["UserID"]; !ok || len(definition.Required) > 0 {
+		t.Errorf("BuildSwagger should have named the property after the field, without required fields (actually %v)", definition)
+	}
+
+	if example, _ := json.Marshal(definition.Example); string(example) != `{"UserID":0}` {
+		t.Errorf("BuildSwagger should have generated the example %s (actually %s)", `{"UserID":0}`, example)
+	}
+
+	if strings.Contains(logs.String(), "Example Error") {
+		t.Errorf("BuildSwagger should have validated the example against the properties of the definition (actually %s)", logs.String())
+	}
+}
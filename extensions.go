@@ -0,0 +1,208 @@
+/**
+ * Vendor Extensions
+ */
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ReservedExtensions are the vendor extensions swagger-gen outputs itself (from @deprecated and @sunset),
+// which cannot be set with a tag
+var ReservedExtensions = []string{
+	"x-deprecated",
+	"x-sunset",
+}
+
+// IsVendorExtension checks if a tag name is a vendor extension (e.g. `x-internal`)
+func IsVendorExtension(name string) bool {
+	return strings.HasPrefix(name, VendorExtensionPrefix) && len(name) > len(VendorExtensionPrefix)
+}
+
+// ParseExtensions collects the vendor extension tags (@x-...) of a comment block
+// If a tag is repeated, its last value is used
+// Values that cannot be parsed are kept as strings, and reserved extensions (see ReservedExtensions) are left out, and both are reported in `err`
+func ParseExtensions(tags map[string][]string) (extensions map[string]interface{}, err error) {
+
+	names := []string{}
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		values := tags[name]
+
+		if !IsVendorExtension(name) {
+			continue
+		}
+
+		if inArray(name, ReservedExtensions) {
+			if err == nil {
+				err = fmt.Errorf("@%s is reserved, use @deprecated and @sunset instead", name)
+			}
+			continue
+		}
+
+		if extensions == nil {
+			extensions = map[string]interface{}{}
+		}
+
+		value, valueErr := ParseExtensionValue(values[len(values)-1])
+		if valueErr != nil && err == nil {
+			err = fmt.Errorf("Invalid value of @%s: %s", name, valueErr.Error())
+		}
+
+		extensions[name] = value
+	}
+
+	return
+}
+
+// ParseExtensionValue parses the value of a vendor extension tag
+// An empty value is `true` (e.g. `@x-internal`), JSON values (objects, arrays, numbers, booleans, strings and null)
+// and YAML objects and lists are parsed, and anything else is used as a plain string
+func ParseExtensionValue(raw string) (value interface{}, err error) {
+
+	raw = strings.TrimSpace(raw)
+
+	if len(raw) == 0 {
+		return true, nil
+	}
+
+	if json.Unmarshal([]byte(raw), &value) == nil {
+		return
+	}
+
+	firstLine := strings.Split(raw, "\n")[0]
+	looksStructured := strings.HasPrefix(raw, "{") ||
+		strings.HasPrefix(raw, "[") ||
+		strings.HasPrefix(raw, "- ") ||
+		(strings.Contains(raw, "\n") && strings.HasSuffix(firstLine, ":")) ||
+		(strings.Contains(raw, "\n") && strings.Contains(firstLine, ": "))
+
+	if !looksStructured {
+		return raw, nil
+	}
+
+	var yamlValue interface{}
+	if err = yaml.Unmarshal([]byte(raw), &yamlValue); err != nil {
+		return raw, err
+	}
+
+	return normalizeYAML(yamlValue), nil
+}
+
+// normalizeYAML converts the `map[interface{}]interface{}` maps decoded by yaml into `map[string]interface{}`
+// so they can be encoded as JSON
+func normalizeYAML(value interface{}) interface{} {
+
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		object := map[string]interface{}{}
+		for key, child := range typed {
+			object[fmt.Sprint(key)] = normalizeYAML(child)
+		}
+		return object
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = normalizeYAML(child)
+		}
+		return typed
+	}
+
+	return value
+}
+
+// marshalWithExtensions adds `extensions` (sorted by name) to the JSON object `data`
+func marshalWithExtensions(data []byte, extensions map[string]interface{}) ([]byte, error) {
+
+	if len(extensions) == 0 {
+		return data, nil
+	}
+
+	names := []string{}
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buffer := bytes.NewBuffer(bytes.TrimSuffix(bytes.TrimSpace(data), []byte("}")))
+	isEmpty := bytes.Equal(bytes.TrimSpace(buffer.Bytes()), []byte("{"))
+
+	for i, name := range names {
+
+		value, err := json.Marshal(extensions[name])
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 || !isEmpty {
+			buffer.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(name)
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// MarshalJSON outputs the operation along with its vendor extensions
+func (p Path) MarshalJSON() ([]byte, error) {
+
+	type path Path
+	data, err := json.Marshal(path(p))
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalWithExtensions(data, p.Extensions)
+}
+
+// MarshalJSON outputs the OpenAPI operation along with its vendor extensions
+func (o OpenAPIOperation) MarshalJSON() ([]byte, error) {
+
+	type operation OpenAPIOperation
+	data, err := json.Marshal(operation(o))
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalWithExtensions(data, o.Extensions)
+}
+
+// MarshalJSON outputs the definition along with its vendor extensions
+func (d ModelDefinition) MarshalJSON() ([]byte, error) {
+
+	type modelDefinition ModelDefinition
+	data, err := json.Marshal(modelDefinition(d))
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalWithExtensions(data, d.Extensions)
+}
+
+// MarshalJSON outputs the property along with its vendor extensions
+func (p Property) MarshalJSON() ([]byte, error) {
+
+	type property Property
+	data, err := json.Marshal(property(p))
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalWithExtensions(data, p.Extensions)
+}
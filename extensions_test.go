@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseExtensionValue(t *testing.T) {
+
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"", `true`},
+		{"false", `false`},
+		{"42", `42`},
+		{"internal only", `"internal only"`},
+		{`{"type": "aws_proxy", "httpMethod": "POST"}`, `{"httpMethod":"POST","type":"aws_proxy"}`},
+		{"- lang: curl\n  source: curl /users", `[{"lang":"curl","source":"curl /users"}]`},
+		{"type: aws_proxy\nhttpMethod: POST", `{"httpMethod":"POST","type":"aws_proxy"}`},
+	}
+
+	for _, test := range tests {
+		value, err := ParseExtensionValue(test.raw)
+		if err != nil {
+			t.Errorf("ParseExtensionValue should have returned a nil error for '%s' (actually '%s')", test.raw, err.Error())
+		}
+		actual, _ := json.Marshal(value)
+		if string(actual) != test.expected {
+			t.Errorf("ParseExtensionValue should have returned %s for '%s' (actually %s)", test.expected, test.raw, string(actual))
+		}
+	}
+
+	if _, err := ParseExtensionValue("{not: [valid"); err == nil {
+		t.Errorf("ParseExtensionValue should have returned an error (actually nil)")
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+
+	extensions, err := ParseExtensions(map[string][]string{
+		"route":      {"GET /users GetUsers"},
+		"x-internal": {""},
+		"x-":         {"ignored"},
+	})
+
+	if err != nil {
+		t.Errorf("ParseExtensions should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if len(extensions) != 1 || extensions["x-internal"] != true {
+		t.Errorf("ParseExtensions should have returned only x-internal: true (actually %v)", extensions)
+	}
+
+	extensions, err = ParseExtensions(map[string][]string{
+		"x-sunset":   {"2027-01-01"},
+		"x-internal": {""},
+	})
+
+	if err == nil || len(extensions) != 1 || extensions["x-internal"] != true {
+		t.Errorf("ParseExtensions should have returned an error and left out the reserved x-sunset (actually %v, %v)", extensions, err)
+	}
+}
+
+func TestPathMarshalJSON(t *testing.T) {
+
+	path := Path{
+		Summary:    "Get users",
+		Responses:  map[string]PathResponse{},
+		Extensions: map[string]interface{}{"x-internal": true, "x-codeSamples": []interface{}{}},
+	}
+
+	data, _ := json.Marshal(path)
+	expected := `{"description":"","summary":"Get users","responses":{},"x-codeSamples":[],"x-internal":true}`
+
+	if string(data) != expected {
+		t.Errorf("Path.MarshalJSON should have returned %s (actually %s)", expected, string(data))
+	}
+
+	data, _ = json.Marshal(Property{Extensions: map[string]interface{}{"x-internal": true}})
+
+	if string(data) != `{"x-internal":true}` {
+		t.Errorf("Property.MarshalJSON should have returned %s (actually %s)", `{"x-internal":true}`, string(data))
+	}
+}
@@ -0,0 +1,238 @@
+/**
+ * Ignore
+ */
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// IgnoreFileName is the name of the files listing the source paths swagger-gen should not scan
+const IgnoreFileName = ".swaggerignore"
+
+// DefaultExcludes are the paths that are never scanned, unless re-included by a `!pattern` in a .swaggerignore file
+var DefaultExcludes = []string{
+	".git/",
+	"vendor/",
+	"node_modules/",
+	"testdata/",
+	"*_test.go",
+}
+
+// IgnoreRule is a single gitignore style pattern
+type IgnoreRule struct {
+	Pattern  string
+	Base     string // directory (relative to the root) of the .swaggerignore file the rule is from
+	Negate   bool   // `!pattern` re-includes paths excluded by an earlier rule
+	DirOnly  bool   // `pattern/` only matches directories
+	Anchored bool   // patterns with a `/` (other than a trailing one) are relative to `Base`, others match at any depth
+}
+
+// FileFilter decides which files and directories are scanned
+type FileFilter struct {
+	Includes []string     // globs files must match (all files if empty)
+	Rules    []IgnoreRule // default exclusions followed by the rules of the .swaggerignore files
+	Excludes []IgnoreRule // -exclude rules, which take precedence over the .swaggerignore files
+	FS       fs.FS        // the filesystem the .swaggerignore files are read from (the OS filesystem if nil)
+}
+
+// NewFileFilter builds a filter from the default exclusions, the .swaggerignore file in `rootPath` of `fsys`
+// (the OS filesystem if nil), and the -include and -exclude globs
+func NewFileFilter(fsys fs.FS, rootPath string, includes []string, excludes []string) (filter *FileFilter, err error) {
+
+	filter = &FileFilter{Includes: includes, FS: fsys}
+
+	for _, pattern := range DefaultExcludes {
+		rule, _ := ParseIgnoreRule(pattern, "")
+		filter.Rules = append(filter.Rules, rule)
+	}
+
+	for _, pattern := range excludes {
+		if rule, ok := ParseIgnoreRule(pattern, ""); ok {
+			filter.Excludes = append(filter.Excludes, rule)
+		}
+	}
+
+	err = filter.AddIgnoreFile(rootPath, "")
+
+	return
+}
+
+// AddIgnoreFile adds the rules of the .swaggerignore file in the directory `dirPath` (`relDir` relative to the root), if there is one
+func (f *FileFilter) AddIgnoreFile(dirPath string, relDir string) error {
+
+	rules, err := ReadIgnoreFile(f.FS, path.Join(dirPath, IgnoreFileName), relDir)
+	f.Rules = append(f.Rules, rules...)
+
+	return err
+}
+
+// ReadIgnoreFile reads the rules of a .swaggerignore file of `fsys` (the OS filesystem if nil). A missing file has no rules
+func ReadIgnoreFile(fsys fs.FS, filePath string, base string) (rules []IgnoreRule, err error) {
+
+	if fsys == nil {
+		fsys = osFS{}
+	}
+
+	file, err := fsys.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := ParseIgnoreRule(scanner.Text(), base); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	err = scanner.Err()
+	return
+}
+
+// ParseIgnoreRule parses a line of a .swaggerignore file
+// Returns false for blank lines and comments
+func ParseIgnoreRule(line string, base string) (rule IgnoreRule, ok bool) {
+
+	line = strings.TrimRight(line, " \t\r")
+
+	if len(line) == 0 || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	rule.Base = base
+
+	if strings.HasPrefix(line, "!") {
+		rule.Negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\#") || strings.HasPrefix(line, "\\!") {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.DirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	if strings.Contains(line, "/") {
+		rule.Anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if len(line) == 0 {
+		return
+	}
+
+	rule.Pattern = line
+	ok = true
+	return
+}
+
+// Match checks if the rule matches a path (relative to the root, separated by `/`)
+func (r IgnoreRule) Match(relPath string, isDir bool) bool {
+
+	if r.DirOnly && !isDir {
+		return false
+	}
+
+	if len(r.Base) > 0 {
+		if !strings.HasPrefix(relPath, r.Base+"/") {
+			return false
+		}
+		relPath = relPath[len(r.Base)+1:]
+	}
+
+	if r.Anchored {
+		return MatchGlob(r.Pattern, relPath)
+	}
+
+	return MatchGlob(r.Pattern, path.Base(relPath))
+}
+
+// Excluded checks if a path (relative to the root, separated by `/`) should not be scanned
+// As in gitignore, the last matching rule wins, and the contents of an excluded directory are never scanned
+func (f *FileFilter) Excluded(relPath string, isDir bool) bool {
+
+	excluded := matchIgnoreRules(f.Rules, relPath, isDir, false)
+	excluded = matchIgnoreRules(f.Excludes, relPath, isDir, excluded)
+
+	if excluded || isDir || len(f.Includes) == 0 {
+		return excluded
+	}
+
+	for _, include := range f.Includes {
+		if rule, ok := ParseIgnoreRule(include, ""); ok && rule.Match(relPath, false) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ExcludedFile checks if a file (relative to the root, separated by `/`) should not be scanned,
+// including when one of the directories it is in is excluded
+func (f *FileFilter) ExcludedFile(relPath string) bool {
+
+	parts := strings.Split(relPath, "/")
+
+	for i := 1; i < len(parts); i++ {
+		if f.Excluded(strings.Join(parts[0:i], "/"), true) {
+			return true
+		}
+	}
+
+	return f.Excluded(relPath, false)
+}
+
+// matchIgnoreRules applies `rules` in order to a path that starts out `excluded` or not
+func matchIgnoreRules(rules []IgnoreRule, relPath string, isDir bool, excluded bool) bool {
+
+	for _, rule := range rules {
+		if rule.Match(relPath, isDir) {
+			excluded = !rule.Negate
+		}
+	}
+
+	return excluded
+}
+
+// MatchGlob matches a path against a glob, where `**` matches any number of directories
+// Examples: `internal/**/*.go` matches `internal/api/users.go`, `**/mocks` matches `pkg/mocks`
+func MatchGlob(pattern string, relPath string) bool {
+	return matchGlobParts(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// matchGlobParts matches the segments of a path against the segments of a glob
+func matchGlobParts(patternParts []string, pathParts []string) bool {
+
+	if len(patternParts) == 0 {
+		return len(pathParts) == 0
+	}
+
+	if patternParts[0] == "**" {
+		for i := 0; i <= len(pathParts); i++ {
+			if matchGlobParts(patternParts[1:], pathParts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(pathParts) == 0 {
+		return false
+	}
+
+	if matched, _ := path.Match(patternParts[0], pathParts[0]); !matched {
+		return false
+	}
+
+	return matchGlobParts(patternParts[1:], pathParts[1:])
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"*.go", "users.go", true},
+		{"internal/**/*.go", "internal/users.go", true},
+		{"internal/**/*.go", "internal/api/v1/users.go", true},
+		{"internal/**/*.go", "cmd/users.go", false},
+		{"**/mocks", "pkg/api/mocks", true},
+		{"api/*.go", "api/v1/users.go", false},
+	}
+
+	for _, test := range tests {
+		if actual := MatchGlob(test.pattern, test.path); actual != test.expected {
+			t.Errorf("MatchGlob(%s, %s) should have returned %t (actually %t)", test.pattern, test.path, test.expected, actual)
+		}
+	}
+}
+
+func TestFileFilterExcluded(t *testing.T) {
+
+	filter, _ := NewFileFilter(nil, "", nil, []string{"mocks/"})
+	rule, _ := ParseIgnoreRule("!testdata/", "")
+	filter.Rules = append(filter.Rules, rule)
+	rule, _ = ParseIgnoreRule("/generated/*.go", "")
+	filter.Rules = append(filter.Rules, rule)
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"vendor", true, true},
+		{"pkg/node_modules", true, true},
+		{".git", true, true},
+		{"api/users_test.go", false, true},
+		{"api/users.go", false, false},
+		{"testdata", true, false},
+		{"generated/users.go", false, true},
+		{"api/generated/users.go", false, false},
+		{"api/mocks", true, true},
+		{"api/mocks.go", false, false},
+	}
+
+	for _, test := range tests {
+		if actual := filter.Excluded(test.path, test.isDir); actual != test.expected {
+			t.Errorf("Excluded(%s) should have returned %t (actually %t)", test.path, test.expected, actual)
+		}
+	}
+
+	filter.Includes = []string{"api/**/*.go"}
+
+	if !filter.Excluded("cmd/main.go", false) {
+		t.Errorf("Excluded(cmd/main.go) should have returned true for a file that is not included")
+	}
+
+	if filter.Excluded("api/v1/users.go", false) || filter.Excluded("cmd", true) {
+		t.Errorf("Excluded should have returned false for included files and directories")
+	}
+}
+
+func TestGetAllFilePaths_Filter(t *testing.T) {
+
+	rootPath, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	for _, filePath := range []string{"main.go", "main_test.go", "vendor/lib/lib.go", "api/users.go", "api/fixtures/fixture.go", "api/legacy.go"} {
+		os.MkdirAll(filepath.Join(rootPath, filepath.Dir(filePath)), 0755)
+		ioutil.WriteFile(filepath.Join(rootPath, filePath), []byte("package main\n"), 0644)
+	}
+
+	ioutil.WriteFile(filepath.Join(rootPath, ".swaggerignore"), []byte("# Fixtures\nfixtures/\n"), 0644)
+	ioutil.WriteFile(filepath.Join(rootPath, "api", ".swaggerignore"), []byte("legacy.go\n"), 0644)
+
+	filter, err := NewFileFilter(nil, rootPath, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sio := &Sio{Filter: filter}
+	sio.GetAllFilePaths(rootPath)
+
+	files := []string{}
+	for _, filePath := range sio.TmpFiles {
+		files = append(files, strings.TrimPrefix(filePath, rootPath+"/"))
+	}
+
+	if strings.Join(files, ",") != "api/users.go,main.go" {
+		t.Errorf("GetAllFilePaths should have returned api/users.go,main.go (actually %s)", strings.Join(files, ","))
+	}
+}
@@ -6,43 +6,85 @@ package main
 
 import (
 	"bufio"
+	"io/fs"
 	"io/ioutil"
 	"log"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
 )
 
 // Sio represents all IO functionality
 type Sio struct {
-	Files    map[string]*SrcFile
-	TmpFiles []string
-	Routes   []Route
-	Models   map[string]Model
+	Files      map[string]*SrcFile
+	TmpFiles   []string
+	Routes     []Route
+	Models     map[string]Model
+	Filter     *FileFilter // excluded files and directories are never read (nil scans everything)
+	Extensions []string    // extensions of the source files to scan (go files if empty)
+	FS         fs.FS       // the filesystem the files are read from (the OS filesystem if nil)
 }
 
-// GetAllFilePaths recursively looks for golang files starting with a root directory at path `rootPath`
-func (s *Sio) GetAllFilePaths(rootPath string) {
-	fileInfos, err := ioutil.ReadDir(rootPath)
+// GetAllFilePaths recursively looks for source files starting with a root directory at path `rootPath`
+func (s *Sio) GetAllFilePaths(rootPath string) error {
+	return s.getFilePaths(rootPath, "")
+}
+
+// getFilePaths looks for source files in the directory `dirPath`, which is `relDir` relative to the root
+func (s *Sio) getFilePaths(dirPath string, relDir string) error {
+	fsys := s.FS
+	if fsys == nil {
+		fsys = osFS{}
+	}
+
+	entries, err := fs.ReadDir(fsys, dirPath)
 
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	for _, fileInfo := range fileInfos {
-		fileName := fileInfo.Name()
-		switch mode := fileInfo.Mode(); {
+	// Nested .swaggerignore files apply to their own directory
+	if s.Filter != nil && len(relDir) > 0 {
+		if err := s.Filter.AddIgnoreFile(dirPath, relDir); err != nil {
+			return err
+		}
+	}
+
+	extensions := s.Extensions
+	if len(extensions) == 0 {
+		extensions = GoLanguage{}.Extensions()
+	}
+
+	for _, entry := range entries {
+		fileName := entry.Name()
+		relPath := path.Join(relDir, fileName)
+		switch mode := entry.Type(); {
 		case mode.IsRegular():
-			// Check for .go extension
-			if fileName[len(fileName)-3:] != ".go" {
+			// Check for a source file extension
+			if !inArray(strings.ToLower(path.Ext(fileName)), extensions) {
+				continue
+			}
+
+			if s.Filter != nil && s.Filter.Excluded(relPath, false) {
 				continue
 			}
 
-			s.TmpFiles = append(s.TmpFiles, rootPath+"/"+fileName)
+			s.TmpFiles = append(s.TmpFiles, path.Join(dirPath, fileName))
 			break
 		case mode.IsDir():
-			s.GetAllFilePaths(rootPath + "/" + fileName)
+			if s.Filter != nil && s.Filter.Excluded(relPath, true) {
+				continue
+			}
+
+			if err := s.getFilePaths(path.Join(dirPath, fileName), relPath); err != nil {
+				return err
+			}
 			break
 		}
 	}
+
+	return nil
 }
 
 // @deprecated
@@ -68,6 +110,40 @@ func readDirFiles(dirPath string) (files []string) {
 	return
 }
 
+// WriteFileAtomic writes `data` to a temporary file next to `filePath`, then renames it to `filePath`,
+// so readers never see a partially written file
+func WriteFileAtomic(filePath string, data []byte) error {
+
+	file, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	// ioutil.TempFile creates files that only the owner can read
+	if err = os.Chmod(file.Name(), 0644); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err = os.Rename(file.Name(), filePath); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return nil
+}
+
 func readLines(path string) ([]string, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -0,0 +1,206 @@
+/**
+ * Java
+ */
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// javaMethodPattern finds the name of a method declaration
+var javaMethodPattern = regexp.MustCompile(`^\s*(?:(?:public|protected|private|static|final|synchronized|abstract|default)\s+)*(?:<[^>]*>\s*)?[\w.<>,?\[\] ]+?\s+(\w+)\s*\(`)
+
+// javaFieldPattern finds the modifiers, type and name of a field declaration
+// Examples: `private String email;`, `public final List<String> tags = new ArrayList<>();`
+var javaFieldPattern = regexp.MustCompile(`^((?:(?:public|protected|private|static|final|transient|volatile)\s+)*)([\w.<>,?\[\] ]+?)\s+(\w+)\s*(?:=.*)?;$`)
+
+// javaAnnotationPattern finds an annotation and its arguments (e.g. `@JsonProperty("first_name")`)
+var javaAnnotationPattern = regexp.MustCompile(`@(\w+)(?:\(([^)]*)\))?`)
+
+// javaAnnotationValuePattern finds the value of an annotation (`"x"` or `value = "x"`)
+var javaAnnotationValuePattern = regexp.MustCompile(`^(?:value\s*=\s*)?"([^"]*)"`)
+
+// javaRequiredAnnotations are the bean validation annotations of required fields
+var javaRequiredAnnotations = []string{"NotNull", "NotBlank", "NotEmpty", "Nonnull"}
+
+// javaTypes are the swagger types of java types
+var javaTypes = map[string]string{
+	"String":         SwaggerTypeString,
+	"char":           SwaggerTypeString,
+	"Character":      SwaggerTypeString,
+	"UUID":           SwaggerTypeString,
+	"Date":           SwaggerTypeString,
+	"LocalDate":      SwaggerTypeString,
+	"LocalDateTime":  SwaggerTypeString,
+	"LocalTime":      SwaggerTypeString,
+	"Instant":        SwaggerTypeString,
+	"OffsetDateTime": SwaggerTypeString,
+	"ZonedDateTime":  SwaggerTypeString,
+	"byte":           SwaggerTypeInt,
+	"Byte":           SwaggerTypeInt,
+	"short":          SwaggerTypeInt,
+	"Short":          SwaggerTypeInt,
+	"int":            SwaggerTypeInt,
+	"Integer":        SwaggerTypeInt,
+	"long":           SwaggerTypeInt,
+	"Long":           SwaggerTypeInt,
+	"BigInteger":     SwaggerTypeInt,
+	"float":          SwaggerTypeFloat,
+	"Float":          SwaggerTypeFloat,
+	"double":         SwaggerTypeFloat,
+	"Double":         SwaggerTypeFloat,
+	"BigDecimal":     SwaggerTypeFloat,
+	"boolean":        SwaggerTypeBool,
+	"Boolean":        SwaggerTypeBool,
+	"Object":         "object",
+	"Map":            "object",
+	"HashMap":        "object",
+	"JsonNode":       "object",
+}
+
+// javaArrayTypes are the java types of collections
+var javaArrayTypes = []string{"List", "ArrayList", "LinkedList", "Set", "HashSet", "TreeSet", "Collection", "Iterable"}
+
+// JavaLanguage reads annotations from java files, with models declared as classes
+// Example:
+//
+//	/**
+//	 * @model User
+//	 */
+//	public class User {
+//	    /** The user's email */
+//	    @NotNull
+//	    private String email;
+//	    @JsonProperty("nick_name")
+//	    private String nickname;
+//	}
+type JavaLanguage struct{}
+
+// Name returns `java`
+func (JavaLanguage) Name() string {
+	return "java"
+}
+
+// Extensions returns the extension of java files
+func (JavaLanguage) Extensions() []string {
+	return []string{".java"}
+}
+
+// CommentSyntax returns the `//` and `/* */` comments of java
+func (JavaLanguage) CommentSyntax() CommentSyntax {
+	return CommentSyntax{LineComments: []string{"//"}, BlockStart: "/*", BlockEnd: "*/"}
+}
+
+// FuncName returns the name of the method declared after the comment block, skipping annotations (e.g. `@GetMapping("/{id}")`)
+func (JavaLanguage) FuncName(lines []string, blockStart int, blockEnd int) string {
+
+	for lineNum := blockEnd + 1; lineNum < len(lines); lineNum++ {
+
+		line := strings.TrimSpace(lines[lineNum])
+		if strings.HasPrefix(line, "@") {
+			continue
+		}
+
+		if matches := javaMethodPattern.FindStringSubmatch(line); matches != nil {
+			return matches[1]
+		}
+		break
+	}
+
+	return ""
+}
+
+// ModelFields returns the instance fields of the class declared after the comment block
+// Fields are required if they have a @NotNull, @NotBlank or @NotEmpty annotation, or @JsonProperty(required = true)
+func (JavaLanguage) ModelFields(lines []string, commentLines []CommentLine, blockStart int, blockEnd int) []ModelField {
+	return classFields(lines, commentLines, blockEnd+1, func(line string, annotations []string, comments []string) (ModelField, bool) {
+		return ParseJavaField(line, annotations)
+	})
+}
+
+// ParseJavaField parses a field declaration, along with the annotations above it and its trailing comment
+// Returns false if the line is not an instance field (e.g. a method or a static field)
+func ParseJavaField(line string, annotations []string) (field ModelField, ok bool) {
+
+	line, field.Description = splitTrailingComment(line, "//")
+
+	// Annotations can also be on the same line as the field
+	for strings.HasPrefix(line, "@") {
+		loc := javaAnnotationPattern.FindStringIndex(line)
+		if loc == nil || loc[0] != 0 {
+			return
+		}
+		annotations = append(annotations, line[0:loc[1]])
+		line = strings.TrimSpace(line[loc[1]:])
+	}
+
+	matches := javaFieldPattern.FindStringSubmatch(line)
+	if matches == nil || strings.Contains(matches[1], "static") {
+		return
+	}
+
+	field.Name = matches[3]
+
+	for _, annotation := range annotations {
+
+		annotationMatches := javaAnnotationPattern.FindStringSubmatch(annotation)
+		if annotationMatches == nil {
+			continue
+		}
+
+		name, args := annotationMatches[1], strings.TrimSpace(annotationMatches[2])
+
+		switch {
+		case inArray(name, javaRequiredAnnotations):
+			field.Required = true
+		case name == "JsonProperty":
+			if valueMatches := javaAnnotationValuePattern.FindStringSubmatch(args); valueMatches != nil {
+				field.StructTag = reflect.StructTag(fmt.Sprintf(`json:"%s"`, valueMatches[1]))
+			}
+			if strings.Contains(strings.Replace(args, " ", "", -1), "required=true") {
+				field.Required = true
+			}
+		case name == "JsonIgnore":
+			field.StructTag = reflect.StructTag(`json:"-"`)
+		}
+	}
+
+	swaggerType, typeName, isArray := javaType(matches[2])
+	setFieldType(&field, swaggerType, typeName, isArray)
+
+	ok = true
+	return
+}
+
+// javaType returns the swagger type of a java type (empty if it is a model), along with the name of the type
+// (or of the type of its items)
+func javaType(javaTypeName string) (swaggerType string, typeName string, isArray bool) {
+
+	typeName = strings.TrimSpace(javaTypeName)
+
+	if strings.HasSuffix(typeName, "[]") {
+		swaggerType, typeName, _ = javaType(strings.TrimSuffix(typeName, "[]"))
+		return swaggerType, typeName, true
+	}
+
+	genericName, args := splitTypeArgs(typeName, "<", ">")
+	if dotIdx := strings.LastIndex(genericName, "."); dotIdx > -1 {
+		genericName = genericName[dotIdx+1:]
+	}
+
+	switch {
+	case inArray(genericName, javaArrayTypes):
+		if len(args) != 1 || args[0] == "?" {
+			return "object", "object", true
+		}
+		swaggerType, typeName, _ = javaType(args[0])
+		return swaggerType, typeName, true
+	case genericName == "Optional" && len(args) == 1:
+		return javaType(args[0])
+	}
+
+	return javaTypes[genericName], genericName, false
+}
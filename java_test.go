@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseJavaField(t *testing.T) {
+
+	expected := map[string]ModelField{
+		"private String email;":                                 {Name: "email", Type: "string"},
+		"@NotNull private Long id; // The id":                   {Name: "id", Type: "integer", Required: true, Description: "The id"},
+		"public final List<String> tags = new ArrayList<>();":   {Name: "tags", Type: "array", ItemsType: "string"},
+		"protected Set<com.acme.Role> roles;":                   {Name: "roles", Type: "array", Ref: "Role"},
+		"private Map<String, List<Integer>> scores;":            {Name: "scores", Type: "object"},
+		"private Address[] addresses;":                          {Name: "addresses", Type: "array", Ref: "Address"},
+		"private Optional<BigDecimal> balance;":                 {Name: "balance", Type: "number"},
+		`private String url = "http://example.com"; // The url`: {Name: "url", Type: "string", Description: "The url"},
+	}
+
+	for line, expectedField := range expected {
+		field, ok := ParseJavaField(line, nil)
+		if !ok || field.Name != expectedField.Name || field.Type != expectedField.Type || field.ItemsType != expectedField.ItemsType || field.Ref != expectedField.Ref || field.Required != expectedField.Required || field.Description != expectedField.Description {
+			t.Errorf("ParseJavaField should have returned %+v for '%s' (actually %+v)", expectedField, line, field)
+		}
+	}
+
+	field, _ := ParseJavaField("private String nickname;", []string{`@JsonProperty(value = "nick_name", required = true)`})
+	if field.JSONName() != "nick_name" || !field.Required {
+		t.Errorf("ParseJavaField should have returned the required field nick_name (actually %+v)", field)
+	}
+
+	for _, line := range []string{"private static final long serialVersionUID = 1L;", "public String getEmail() {"} {
+		if _, ok := ParseJavaField(line, nil); ok {
+			t.Errorf("ParseJavaField should not have returned a field for '%s'", line)
+		}
+	}
+}
+
+func TestParseLines_Java(t *testing.T) {
+
+	lines := []string{
+		"/**",
+		" * A user",
+		" * @model User",
+		" */",
+		"@Entity",
+		"public class User {",
+		"    /**",
+		"     * The email",
+		"     * @format email",
+		"     */",
+		"    @NotBlank",
+		"    private String email;",
+		"",
+		"    public String getEmail() {",
+		"        String email = this.email;",
+		"        return email;",
+		"    }",
+		"",
+		"    private int age;",
+		"}",
+		"",
+		"@RestController",
+		"public class UserController {",
+		"    /**",
+		"     * @route GetUser GET /users/{id}",
+		"     * @return 200 User",
+		"     */",
+		`    @GetMapping("/users/{id}")`,
+		"    public ResponseEntity<User> getUser(@PathVariable String id) {",
+		"    }",
+		"}",
+	}
+
+	result := ParseLines(lines, "src/User.java", false)
+
+	model := result.Models["User"]
+	if model.Description != "A user" || len(model.Fields) != 2 {
+		t.Fatalf("ParseLines should have returned the model User with 2 fields (actually %+v)", model)
+	}
+
+	if model.Fields[0].Name != "email" || !model.Fields[0].Required || model.Fields[0].Description != "The email" || model.Fields[0].Tags[TagFormat][0] != "email" {
+		t.Errorf("ParseLines should have returned the required field email with its comment (actually %+v)", model.Fields[0])
+	}
+
+	if model.Fields[1].Name != "age" || model.Fields[1].Type != "integer" {
+		t.Errorf("ParseLines should have returned the field age (actually %+v)", model.Fields[1])
+	}
+
+	if routes := result.Routes["/users/{id}"]; len(routes) != 1 || routes[0].Handler != "getUser" {
+		t.Errorf("ParseLines should have returned the route GetUser handled by getUser (actually %+v)", result.Routes)
+	}
+}
@@ -0,0 +1,296 @@
+/**
+ * Languages
+ */
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Language is the support for annotations in the source files of a programming language
+type Language interface {
+	// Name is the name of the language, as given to -languages (e.g. `go`, `typescript`)
+	Name() string
+
+	// Extensions are the file extensions of the language's source files (e.g. `.go`)
+	Extensions() []string
+
+	// CommentSyntax is how comments are written in the language
+	CommentSyntax() CommentSyntax
+
+	// FuncName returns the name of the function documented by the comment block from `blockStart` to `blockEnd`,
+	// or an empty string if there is none
+	FuncName(lines []string, blockStart int, blockEnd int) string
+
+	// ModelFields returns the fields of the struct, class or interface documented by the comment block
+	// from `blockStart` to `blockEnd`
+	ModelFields(lines []string, commentLines []CommentLine, blockStart int, blockEnd int) []ModelField
+}
+
+// DefaultLanguages are the languages scanned when -languages is not set
+var DefaultLanguages = []string{"go"}
+
+// Languages are the built in languages, by name
+var Languages = map[string]Language{}
+
+// RegisterLanguage adds a language to the ones swagger-gen can scan
+func RegisterLanguage(language Language) {
+	Languages[language.Name()] = language
+}
+
+func init() {
+	RegisterLanguage(GoLanguage{})
+	RegisterLanguage(TypeScriptLanguage{})
+	RegisterLanguage(PythonLanguage{})
+	RegisterLanguage(JavaLanguage{})
+	RegisterLanguage(PHPLanguage{})
+}
+
+// LanguageNames returns the names of the registered languages, sorted
+func LanguageNames() (names []string) {
+
+	for name := range Languages {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return
+}
+
+// LanguageExtensions returns the file extensions of the languages named `names`
+func LanguageExtensions(names []string) (extensions []string, err error) {
+
+	for _, name := range names {
+		language, ok := Languages[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("Unknown language '%s' (expected one of %s)", name, strings.Join(LanguageNames(), ", "))
+		}
+		extensions = append(extensions, language.Extensions()...)
+	}
+
+	return
+}
+
+// LanguageForFile returns the language of a source file, based on its extension
+// Files with an unknown extension are treated as go files
+func LanguageForFile(filePath string) Language {
+
+	extension := strings.ToLower(filepath.Ext(filePath))
+
+	for _, name := range LanguageNames() {
+		if inArray(extension, Languages[name].Extensions()) {
+			return Languages[name]
+		}
+	}
+
+	return GoLanguage{}
+}
+
+// GoLanguage reads annotations from go files
+type GoLanguage struct{}
+
+// Name returns `go`
+func (GoLanguage) Name() string {
+	return "go"
+}
+
+// Extensions returns the extension of go files
+func (GoLanguage) Extensions() []string {
+	return []string{".go"}
+}
+
+// CommentSyntax returns the `//` and `/* */` comments of go
+func (GoLanguage) CommentSyntax() CommentSyntax {
+	return CommentSyntax{LineComments: []string{"//"}, BlockStart: "/*", BlockEnd: "*/"}
+}
+
+// FuncName returns the name of the function declared on the line after the comment block
+func (GoLanguage) FuncName(lines []string, blockStart int, blockEnd int) string {
+
+	if blockEnd+1 < len(lines) {
+		return GetFuncName(lines[blockEnd+1])
+	}
+
+	return ""
+}
+
+// ModelFields returns the fields of the struct declared after the comment block (see ParseModelField)
+func (GoLanguage) ModelFields(lines []string, commentLines []CommentLine, blockStart int, blockEnd int) (fields []ModelField) {
+
+	// Comment lines above a field describe it
+	fieldComments := []string{}
+
+	for currentLine := blockEnd + 1; currentLine < len(lines); currentLine++ {
+
+		line := strings.TrimSpace(lines[currentLine])
+
+		if len(line) == 0 {
+			continue
+		}
+
+		if len(line) > 4 && line[0:5] == "type " {
+			continue
+		}
+
+		if line == "}" {
+			break
+		}
+
+		if commentLine := commentLines[currentLine]; commentLine.IsComment {
+			if !commentLine.Skip {
+				fieldComments = append(fieldComments, strings.TrimSpace(commentLine.Text))
+			}
+			continue
+		}
+
+		if field, ok := ParseModelField(line); ok {
+			fields = append(fields, describeField(field, fieldComments))
+		}
+
+		fieldComments = []string{}
+	}
+
+	return
+}
+
+// describeField parses the comments above a field along with its trailing comment (held by its description)
+// Field comments can hold tags (e.g. `@example jane@example.com`) as well as the description
+func describeField(field ModelField, comments []string) ModelField {
+
+	commentLines := append(append([]string{}, comments...), splitInlineTags(field.Description)...)
+	field.Tags, field.Description = parseCommentBlock(commentLines)
+
+	return field
+}
+
+// setFieldType sets the type of a field from the swagger type of its type (empty if the type is a model)
+func setFieldType(field *ModelField, swaggerType string, typeName string, isArray bool) {
+
+	switch {
+	case isArray && len(swaggerType) > 0:
+		field.Type = SwaggerTypeArray
+		field.ItemsType = swaggerType
+	case len(swaggerType) > 0:
+		field.Type = swaggerType
+	case isArray:
+		field.Type = SwaggerTypeArray
+		field.Ref = typeName
+	default:
+		field.Type = "#object"
+		field.Ref = typeName
+	}
+}
+
+// splitTrailingComment splits a line of code from its trailing comment starting with `marker`,
+// ignoring markers inside quoted strings
+func splitTrailingComment(line string, marker string) (code string, comment string) {
+
+	quote := rune(0)
+
+	for i, char := range line {
+		switch {
+		case quote != 0:
+			if char == quote && (i == 0 || line[i-1] != '\\') {
+				quote = 0
+			}
+		case char == '"' || char == '\'' || char == '`':
+			quote = char
+		case strings.HasPrefix(line[i:], marker):
+			return strings.TrimSpace(line[0:i]), strings.TrimSpace(line[i+len(marker):])
+		}
+	}
+
+	return strings.TrimSpace(line), ""
+}
+
+// classFields walks the body of the class (or interface) declared after `startLine` in a language with `{ }` blocks
+// `parseField` parses a line declaring a member of the class, along with the annotations (e.g. `@NotNull`)
+// and the comments on the lines above it
+func classFields(lines []string, commentLines []CommentLine, startLine int, parseField func(line string, annotations []string, comments []string) (ModelField, bool)) (fields []ModelField) {
+
+	depth := 0
+	fieldComments := []string{}
+	annotations := []string{}
+
+	for currentLine := startLine; currentLine < len(lines); currentLine++ {
+
+		commentLine := commentLines[currentLine]
+		line := strings.TrimSpace(lines[currentLine])
+
+		if len(line) == 0 {
+			continue
+		}
+
+		if commentLine.IsComment {
+			if depth == 1 && !commentLine.Skip {
+				fieldComments = append(fieldComments, strings.TrimSpace(commentLine.Text))
+			}
+			continue
+		}
+
+		// The declaration of the class
+		if depth == 0 {
+			if strings.Contains(line, "{") {
+				depth = 1
+			}
+			continue
+		}
+
+		if depth == 1 {
+			if strings.HasPrefix(line, "@") || strings.HasPrefix(line, "#[") {
+				annotations = append(annotations, line)
+				continue
+			}
+
+			if field, ok := parseField(line, annotations, fieldComments); ok {
+				fields = append(fields, describeField(field, fieldComments))
+			}
+
+			fieldComments = []string{}
+			annotations = []string{}
+		}
+
+		code, _ := splitTrailingComment(line, "//")
+		depth = depth + strings.Count(code, "{") - strings.Count(code, "}")
+
+		if depth <= 0 {
+			break
+		}
+	}
+
+	return
+}
+
+// splitTypeArgs splits the type arguments of a generic type
+// Example: `Map<String, List<Integer>>` => `Map`, [`String`, `List<Integer>`]
+func splitTypeArgs(typeName string, open string, close string) (name string, args []string) {
+
+	openIdx := strings.Index(typeName, open)
+	if openIdx < 0 || !strings.HasSuffix(typeName, close) {
+		return strings.TrimSpace(typeName), nil
+	}
+
+	name = strings.TrimSpace(typeName[0:openIdx])
+	inner := typeName[openIdx+len(open) : len(typeName)-len(close)]
+
+	depth := 0
+	argStart := 0
+	for i, char := range inner {
+		switch {
+		case strings.ContainsRune("<[(", char):
+			depth = depth + 1
+		case strings.ContainsRune(">])", char):
+			depth = depth - 1
+		case char == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(inner[argStart:i]))
+			argStart = i + 1
+		}
+	}
+	args = append(args, strings.TrimSpace(inner[argStart:]))
+
+	return
+}
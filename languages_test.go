@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLanguageForFile(t *testing.T) {
+
+	expected := map[string]string{
+		"api/users.go":         "go",
+		"src/users.ts":         "typescript",
+		"src/Users.TSX":        "typescript",
+		"app/users.py":         "python",
+		"src/User.java":        "java",
+		"src/User.php":         "php",
+		"docs/description.txt": "go",
+	}
+
+	for filePath, name := range expected {
+		if language := LanguageForFile(filePath); language.Name() != name {
+			t.Errorf("LanguageForFile should have returned %s for %s (actually %s)", name, filePath, language.Name())
+		}
+	}
+}
+
+func TestLanguageExtensions(t *testing.T) {
+
+	extensions, err := LanguageExtensions([]string{"go", " Python"})
+	if err != nil || strings.Join(extensions, ",") != ".go,.py" {
+		t.Errorf("LanguageExtensions should have returned .go,.py (actually %s, %v)", strings.Join(extensions, ","), err)
+	}
+
+	if _, err = LanguageExtensions([]string{"cobol"}); err == nil {
+		t.Errorf("LanguageExtensions should have returned an error for an unknown language")
+	}
+}
+
+func TestSplitTrailingComment(t *testing.T) {
+
+	code, comment := splitTrailingComment(`url = "http://example.com"  # The home page`, "#")
+	if code != `url = "http://example.com"` || comment != "The home page" {
+		t.Errorf("splitTrailingComment should have split the code from the comment (actually '%s', '%s')", code, comment)
+	}
+
+	code, comment = splitTrailingComment(`private String url = "http://example.com";`, "//")
+	if code != `private String url = "http://example.com";` || comment != "" {
+		t.Errorf("splitTrailingComment should have ignored the marker inside a string (actually '%s', '%s')", code, comment)
+	}
+}
+
+func TestExtractComments_Python(t *testing.T) {
+
+	lines := []string{
+		"# @route GetFoo GET /foo",
+		`@router.get("/foo")`,
+		"def get_foo():",
+		`    """`,
+		"    Gets a foo",
+		`    """`,
+		`    """@model Foo"""`,
+	}
+
+	comments := extractComments(lines, PythonLanguage{}.CommentSyntax())
+
+	expected := []CommentLine{
+		{"@route GetFoo GET /foo", true, false},
+		{"", false, false},
+		{"", false, false},
+		{"", true, true},
+		{"Gets a foo", true, false},
+		{"", true, true},
+		{"@model Foo", true, false},
+	}
+
+	for i, commentLine := range comments {
+		if commentLine != expected[i] {
+			t.Errorf("extractComments should have returned %v for line %d (actually %v)", expected[i], i, commentLine)
+		}
+	}
+}
+
+func TestParseLines_Languages(t *testing.T) {
+
+	lines := []string{
+		"# @route GetFoo GET /foo",
+		`@router.get("/foo")`,
+		"def get_foo():",
+		"    pass",
+	}
+
+	result := ParseLines(lines, "app/foo.py", true)
+
+	if len(result.Diagnostics) > 0 {
+		t.Errorf("ParseLines should not have reported the decorator as a route (actually %v)", result.Diagnostics)
+	}
+
+	if routes := result.Routes["/foo"]; len(routes) != 1 || routes[0].Handler != "get_foo" {
+		t.Errorf("ParseLines should have returned the route GetFoo handled by get_foo (actually %v)", result.Routes)
+	}
+}
@@ -4,22 +4,65 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path"
+	"strings"
+	"time"
 
 	yaml "gopkg.in/yaml.v2"
 )
 
+// Version is the version of swagger-gen. Cached parse results are only reused by the same version
+const Version = "0.2.0"
+
+// CommandWatch is the subcommand that regenerates the swagger file whenever the source changes
+const CommandWatch = "watch"
+
 func main() {
 
 	help := flag.Bool("h", false, "Help")
 	init := flag.Bool("i", false, "Initialize the swagger-meta.json file with default values")
-	sourceDir := flag.String("s", ".", "The root of the source code you want swagger-gen to scan and build a swagger spec from. Defaults to current directory")
+	sourceDirs := stringsFlag{}
+	flag.Var(&sourceDirs, "s", "The root of the source code you want swagger-gen to scan and build a swagger spec from. Can be repeated. Defaults to current directory")
+	rev := flag.String("rev", "", "Git revision (e.g. main, v1.2.0) to read the source directories from, without checking it out")
+	metaPath := flag.String("meta", "", "The path of the swagger-meta.json file. Defaults to swagger-meta.json in the first source directory")
+	followReplace := flag.Bool("follow-replace", false, "Also scan the local directories of the replace directives in the go.mod file of each source directory")
+	packages := stringsFlag{}
+	flag.Var(&packages, "packages", "go list package pattern (e.g. ./...) used to find the files of each source directory instead of walking it. Can be repeated")
 	outDir := flag.String("o", ".", "The path to the directory where the generated swagger file will be output to. Defaults to current directory")
 	format := flag.String("f", "json", "Output format. json | yaml. Defaults to json")
+	openAPI3 := flag.Bool("openapi3", false, "Output an OpenAPI 3 document instead of a swagger 2.0 spec")
+	outPaths := stringsFlag{}
+	flag.Var(&outPaths, "out", "Path of the generated swagger file (e.g. api/openapi.yaml), whose format is given by its extension. - writes to stdout (in the -f format). Can be repeated. Defaults to swagger.json (or swagger.yaml) in the -o directory")
+	excludeDeprecated := flag.Bool("exclude-deprecated", false, "Leave deprecated routes (@deprecated) out of the generated swagger file")
+	applyParams := flag.String("apply-params", "", "Comma separated names of reusable parameters (@paramdef) to add to every operation")
+	applyResponses := flag.String("apply-responses", "", "Comma separated code=name pairs of reusable responses (@responsedef) to add to every operation. E.g. 401=Unauthorized")
+	tagOrder := flag.String("tag-order", TagOrderDeclared, "Order of the tags in the generated swagger file. declared | alpha | comma separated tag names. Defaults to declared")
+	discover := flag.Bool("discover", false, "Fill in @route verbs and paths from router registration calls (gorilla/mux, chi, echo, gin)")
+	examples := flag.Bool("examples", false, "Generate examples for models and responses that do not have one")
+	includes := stringsFlag{}
+	flag.Var(&includes, "include", "Glob of the files to scan, relative to the source directory (e.g. internal/**/*.go). Can be repeated")
+	excludes := stringsFlag{}
+	flag.Var(&excludes, "exclude", "Gitignore style pattern of the files and directories not to scan (e.g. mocks/). Can be repeated")
+	languages := flag.String("languages", strings.Join(DefaultLanguages, ","), "Comma separated languages of the source files to scan. go | typescript | python | java | php. Defaults to go")
+	workers := flag.Int("workers", 0, "Number of files to parse concurrently. Defaults to the number of CPUs")
+	check := flag.Bool("check", false, "Compare the generated swagger spec with the existing output files instead of writing them, and exit with status 1 if they are out of date")
+	noCache := flag.Bool("no-cache", false, "Parse every file instead of reusing the results of unchanged files from the build cache")
+	cachePath := flag.String("cache", "", "Path of the build cache file. Defaults to a file in the user cache directory for the first source directory")
+
+	interval := flag.Duration("interval", 500*time.Millisecond, "watch: Time between two checks for changed files")
+	debounce := flag.Duration("debounce", 300*time.Millisecond, "watch: Wait until no file has changed for this long before rebuilding")
 
-	flag.Parse()
+	// Subcommand (swagger-gen watch ...)
+	args := os.Args[1:]
+	watch := len(args) > 0 && args[0] == CommandWatch
+	if watch {
+		args = args[1:]
+	}
+
+	flag.CommandLine.Parse(args)
 
 	if *help == true {
 		fmt.Println(`
@@ -31,16 +74,62 @@ func main() {
 
 				// Generate swagger documentation
 				swagger-gen -s path/to/src -o path/to/out -f json
+
+				// Fill in @route methods and paths from router registrations
+				swagger-gen -s path/to/src -o path/to/out -discover
+
+				// Write json and yaml files at custom paths, and print the json to stdout
+				swagger-gen -s path/to/src -out api/swagger.json -out api/openapi.yaml -out -
+
+				// Also scan typescript and python files
+				swagger-gen -s path/to/src -o path/to/out -languages go,typescript,python
+
+				// Generate swagger documentation from an archive, or from a git revision
+				swagger-gen -s sources.tar.gz -o path/to/out
+				swagger-gen -s path/to/src -rev v1.2.0 -o path/to/out
+
+				// Fail (exit status 1) if the committed swagger file is out of date
+				swagger-gen -s path/to/src -o path/to/out -check
+
+				// Regenerate the swagger documentation whenever the source changes
+				swagger-gen watch -s path/to/src -o path/to/out
+
+				// Generate an OpenAPI 3 document
+				swagger-gen -s path/to/src -out api/openapi.yaml -openapi3
 		
 		`)
 		return
 	}
 
-	if _, dirErr := os.Stat(path.Dir(*sourceDir)); os.IsNotExist(dirErr) {
-		log.Fatal(dirErr)
+	if len(sourceDirs) == 0 {
+		sourceDirs = append(sourceDirs, ".")
+	}
+
+	if len(*rev) == 0 {
+		for _, sourceDir := range sourceDirs {
+			if _, dirErr := os.Stat(path.Dir(sourceDir)); os.IsNotExist(dirErr) {
+				log.Fatal(dirErr)
+			}
+		}
+	}
+
+	// The build cache is kept for the first source directory as given (or the archive), before it is opened
+	defaultCachePath := DefaultCachePath(sourceDirs[0])
+
+	// Sources read from an archive or a git revision (nil if they are read from the OS filesystem)
+	sourceFS, sourceDirs, sourcesErr := OpenSources(sourceDirs, *rev)
+	if sourcesErr != nil {
+		log.Fatal(sourcesErr)
 	}
 
-	swaggerMetaPath := path.Join(*sourceDir, "swagger-meta.json")
+	if sourceFS != nil && (*init || watch || *followReplace) {
+		log.Fatal("-i, watch and -follow-replace cannot be used with sources read from an archive or a git revision")
+	}
+
+	swaggerMetaPath := *metaPath
+	if len(swaggerMetaPath) == 0 {
+		swaggerMetaPath = path.Join(sourceDirs[0], "swagger-meta.json")
+	}
 
 	// Init command (-i)
 	if *init == true {
@@ -66,75 +155,196 @@ func main() {
 		swagger.Schemes = []string{
 			"http",
 		}
-		toJSON(swagger, swaggerMetaPath)
+		swagger.Consumes = []string{
+			MimeTypeJSON,
+		}
+		swagger.Produces = []string{
+			MimeTypeJSON,
+		}
+		if err := WriteOutputs(swagger, []Output{{swaggerMetaPath, "json"}}); err != nil {
+			log.Fatal(err)
+		}
 		log.Printf("Swagger meta file generated at path %s", swaggerMetaPath)
 		os.Exit(0)
 	}
 
-	jsonBytes, jsonBytesErr := ReadJSONToBytes(swaggerMetaPath)
+	var jsonBytes []byte
+	var jsonBytesErr error
+
+	// The default swagger-meta.json file is read from the archive or git revision
+	if sourceFS != nil && len(*metaPath) == 0 {
+		jsonBytes, jsonBytesErr = fs.ReadFile(sourceFS, swaggerMetaPath)
+	} else {
+		jsonBytes, jsonBytesErr = ReadJSONToBytes(swaggerMetaPath)
+	}
 
 	if jsonBytesErr != nil {
 		log.Fatal(jsonBytesErr)
 	}
 
 	swaggerf := Swaggerf{}
+	swaggerf.FS = sourceFS
+	swaggerf.DiscoverRoutes = *discover
+	swaggerf.ExcludeDeprecated = *excludeDeprecated
+	swaggerf.TagOrder = *tagOrder
+	swaggerf.GenerateExamples = *examples
+	swaggerf.Include = includes
+	swaggerf.Exclude = excludes
+	swaggerf.Languages = strings.Split(*languages, ",")
+	swaggerf.Workers = *workers
+	swaggerf.Packages = packages
+	swaggerf.OpenAPI3 = *openAPI3
+
+	// Modules pulled in with a local replace directive are scanned as extra source directories
+	if *followReplace {
+		for _, sourceDir := range sourceDirs {
+			replaceDirs, err := ModuleReplaceDirs(path.Join(sourceDir, "go.mod"))
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, replaceDir := range replaceDirs {
+				if !inArray(replaceDir, sourceDirs) {
+					sourceDirs = append(sourceDirs, replaceDir)
+				}
+			}
+		}
+	}
+
+	for _, name := range strings.Split(*applyParams, ",") {
+		if name = strings.TrimPrefix(strings.TrimSpace(name), ReusablePrefix); len(name) > 0 {
+			swaggerf.ApplyParams = append(swaggerf.ApplyParams, name)
+		}
+	}
+
+	var applyResponsesErr error
+	if swaggerf.ApplyResponses, applyResponsesErr = ParseApplyResponses(*applyResponses); applyResponsesErr != nil {
+		log.Fatal(applyResponsesErr)
+	}
+
+	if *format != "json" && *format != "yaml" {
+		log.Fatal("Invalid output format. Should be `json` or `yaml`")
+	}
+
+	outputs := []Output{}
+	for _, outPath := range outPaths {
+		output, outputErr := ParseOutput(outPath, *format)
+		if outputErr != nil {
+			log.Fatal(outputErr)
+		}
+		outputs = append(outputs, output)
+	}
+
+	if len(outputs) == 0 {
+		outputs = append(outputs, Output{path.Join(*outDir, "swagger."+*format), *format})
+	}
+
+	if *check && watch {
+		log.Fatal("-check cannot be used with watch")
+	}
+
+	// -check does not write any file, not even the build cache
+	if !*noCache && !*check {
+		swaggerf.CachePath = *cachePath
+		if len(swaggerf.CachePath) == 0 {
+			swaggerf.CachePath = defaultCachePath
+		}
+	}
+
+	if watch {
+		swaggerf.Watch(sourceDirs, swaggerMetaPath, outputs, *interval, *debounce)
+		return
+	}
 
 	swaggerf.ParseSwaggerConfig(jsonBytes)
 
 	// Build the swagger object
-	swaggerf.BuildSwagger(*sourceDir)
-	var outFile string
-	switch *format {
-	case "json":
-		outFile = "swagger.json"
-		outPath := path.Join(*outDir, outFile)
-		toJSON(swaggerf.Swagger, outPath)
-	case "yaml":
-		outFile = "swagger.yaml"
-		outPath := path.Join(*outDir, outFile)
-		toYAML(swaggerf.Swagger, outPath)
-	default:
-		log.Fatal("Invalid output format. Should be `json` or `yaml`")
+	if err := swaggerf.BuildSwagger(sourceDirs...); err != nil {
+		log.Fatal(err)
+	}
+
+	if *check {
+		if !checkOutputs(swaggerf.Spec(), outputs) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := WriteOutputs(swaggerf.Spec(), outputs); err != nil {
+		log.Fatal(err)
 	}
 }
 
-func toYAML(swagger Swagger, outFile string) {
-	data, _ := yaml.Marshal(&swagger)
-	file, _ := os.OpenFile(
-		outFile,
-		os.O_WRONLY|os.O_TRUNC|os.O_CREATE,
-		0666,
-	)
-	defer file.Close()
-	_, outFileErr := file.Write(data)
-	if outFileErr != nil {
-		log.Fatal(outFileErr)
+// checkOutputs prints the changes regenerating each output file would make (see CheckOutput)
+// and returns false if any of them is out of date
+func checkOutputs(spec interface{}, outputs []Output) bool {
+
+	upToDate := true
+	checked := 0
+
+	for _, output := range outputs {
+
+		if output.Path == StdoutPath {
+			continue
+		}
+		checked = checked + 1
+
+		changes, err := CheckOutput(spec, output)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if len(changes) == 0 {
+			log.Printf("%s is up to date", output.Path)
+			continue
+		}
+
+		upToDate = false
+		fmt.Printf("%s is out of date (%d changes):\n", output.Path, len(changes))
+		for _, change := range changes {
+			fmt.Printf("    %s\n", change)
+		}
+	}
+
+	if checked == 0 {
+		log.Fatal("-check needs an output file to compare the swagger spec with")
 	}
+
+	if !upToDate {
+		fmt.Println("Run swagger-gen to regenerate the swagger spec")
+	}
+
+	return upToDate
+}
+
+// stringsFlag is a flag that can be repeated, collecting each value
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
 }
 
-func toJSON(swagger Swagger, outFile string) {
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
 
-	// log.Printf("Outfile Extension: %s", path.Ext(outFile))
-	// log.Printf("Outfile base: %s", path.Base(outFile))
-	// log.Printf("Outfile dir: %s", path.Dir(outFile))
+// MarshalSwagger encodes the swagger object (or OpenAPI document) as json (indented) or yaml
+func MarshalSwagger(swagger interface{}, format string) ([]byte, error) {
 
-	if _, dirErr := os.Stat(path.Dir(outFile)); os.IsNotExist(dirErr) {
-		log.Fatal(dirErr)
+	if format == "json" {
+		return json.MarshalIndent(swagger, "", "    ")
 	}
 
-	data, err := json.MarshalIndent(swagger, "", "    ")
+	// The swagger object is encoded through JSON so the YAML uses the same keys (json struct tags and vendor extensions)
+	jsonData, err := json.Marshal(swagger)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	file, _ := os.OpenFile(
-		outFile,
-		os.O_WRONLY|os.O_TRUNC|os.O_CREATE,
-		0666,
-	)
-	defer file.Close()
-	log.Printf("Writing swagger definition to %s", outFile)
-	_, outFileErr := file.Write(data)
-	if outFileErr != nil {
-		log.Fatal(outFileErr)
+
+	document := yaml.MapSlice{}
+	if err = yaml.Unmarshal(jsonData, &document); err != nil {
+		return nil, err
 	}
+
+	return yaml.Marshal(document)
 }
@@ -6,14 +6,14 @@ package main
 import (
 	"errors"
 	"log"
+	"reflect"
+	"regexp"
 	"strings"
 )
 
 // GetModels searches `lines` for @model tags and adds them to the swagger object
 func GetModels(lines []string, filePath string) (models map[string]Model, err error) {
 
-	models = map[string]Model{}
-
 	var symbols []Symbol
 
 	// Routes
@@ -29,88 +29,190 @@ func GetModels(lines []string, filePath string) (models map[string]Model, err er
 		return
 	}
 
-	totalLineLen := len(lines)
+	models = getModels(GoLanguage{}, lines, ExtractComments(lines), filePath, symbols, log.Printf)
+
+	return
+}
+
+// getModels parses the models of the @model `symbols` found in `lines` (whose comments are `commentLines`)
+// of a file written in `language`, reporting errors with `report`
+func getModels(language Language, lines []string, commentLines []CommentLine, filePath string, symbols []Symbol, report reportFunc) (models map[string]Model) {
+
+	models = map[string]Model{}
 
 	for _, symbol := range symbols {
-		comments, _, endLine := GetCommentBlock(lines, symbol.LineNum)
-		tagMap := ParseSymbols(comments)
+		comments, blockStart, endLine := getCommentBlock(commentLines, symbol.LineNum)
+		tagMap, description := parseCommentBlock(comments)
 
 		// Assume that after the end line will be the start of the model definition
 		currentLine := endLine + 1
-		model := Model{}
+		model := Model{FilePath: filePath, LineNum: currentLine}
 		if _, ok := tagMap["model"]; !ok {
-			log.Printf("No model tag found at filePath %s", filePath)
+			report("No model tag found at filePath %s", filePath)
 			continue
 		}
 
 		model.Name = tagMap["model"][0]
+		model.Description = description
 
-		for {
+		if descriptions, ok := tagMap[TagDescription]; ok {
+			model.Description = strings.Join(descriptions, "\n\n")
+		}
 
-			if totalLineLen <= currentLine {
-				break
-			}
+		if examples, ok := tagMap[TagExample]; ok {
+			model.Example = examples[0]
+		}
 
-			if len(lines[currentLine]) == 0 {
-				currentLine = currentLine + 1
-				continue
-			}
+		deprecation, deprecationErr := ParseDeprecation(tagMap)
+		if deprecationErr != nil {
+			report("Model Error: %s (File: %s; Line: %d)", deprecationErr.Error(), filePath, currentLine)
+		}
+		model.Deprecation = deprecation
 
-			line := strings.TrimLeft(lines[currentLine], " ")
-			if len(line) > 4 && line[0:5] == "type " {
-				currentLine = currentLine + 1
-				continue
-			}
+		extensions, extensionsErr := ParseExtensions(tagMap)
+		if extensionsErr != nil {
+			report("Model Error: %s (File: %s; Line: %d)", extensionsErr.Error(), filePath, currentLine)
+		}
+		model.Extensions = extensions
 
-			if strings.Trim(lines[currentLine], " ") == "}" {
-				break
-			}
+		model.Fields = language.ModelFields(lines, commentLines, blockStart, endLine)
 
-			fieldLineParts := strings.Fields(strings.TrimPrefix(lines[currentLine], " "))
-			fieldType := ""
-			isArray := false
+		models[model.Name] = model
 
-			if fieldLineParts[1][0:2] == "[]" {
-				fieldLineParts[1] = fieldLineParts[1][2:]
-				isArray = true
-			}
+	}
 
-			if strings.Contains(fieldLineParts[1], ".") {
-				fieldParts := strings.Split(fieldLineParts[1], ".")
-				fieldLineParts[1] = fieldParts[len(fieldParts)-1]
-			}
+	return
+}
 
-			fieldRef := ""
-			// float, int
-			switch {
-			case len(fieldLineParts[1]) > 4 && fieldLineParts[1][0:5] == "float":
-				fieldType = "number"
-			case fieldLineParts[1][0:3] == "int":
-				fieldType = "integer"
-			case fieldLineParts[1] == "string":
-				fieldType = "string"
-			default:
-				if isArray == true {
-					fieldType = "array"
-					fieldRef = fieldLineParts[1]
-				} else {
-					fieldType = "#object"
-					fieldRef = fieldLineParts[1]
-				}
-			}
+// embeddedStructPattern matches the type of an embedded struct, optionally a pointer and from another package
+var embeddedStructPattern = regexp.MustCompile(`^\*?([A-Za-z_]\w*\.)?[A-Za-z_]\w*$`)
+
+// ParseModelField parses a struct field line, along with its struct tag and trailing comment
+// Embedded structs (e.g. `Pagination` or `*models.Pagination`) are returned with `Embedded` set and their type in `Ref`
+// Returns false if the line is not a field
+// Example: Page int `form:"page" binding:"required"` // The page number
+func ParseModelField(line string) (field ModelField, ok bool) {
+
+	if commentIdx := strings.Index(line, "//"); commentIdx > -1 && strings.Count(line[0:commentIdx], "`")%2 == 0 {
+		field.Description = strings.TrimSpace(line[commentIdx+2:])
+		line = line[0:commentIdx]
+	}
+
+	if tagStart := strings.Index(line, "`"); tagStart > -1 {
+		if tagEnd := strings.LastIndex(line, "`"); tagEnd > tagStart {
+			field.StructTag = reflect.StructTag(line[tagStart+1 : tagEnd])
+		}
+		line = line[0:tagStart]
+	}
+
+	fieldLineParts := strings.Fields(line)
+
+	if len(fieldLineParts) == 1 && embeddedStructPattern.MatchString(fieldLineParts[0]) {
+		typeParts := strings.Split(strings.TrimPrefix(fieldLineParts[0], "*"), ".")
+		field.Name = typeParts[len(typeParts)-1]
+		field.Type = "#object"
+		field.Ref = field.Name
+		field.Embedded = true
+		ok = true
+		return
+	}
+
+	if len(fieldLineParts) < 2 {
+		return
+	}
+
+	field.Name = fieldLineParts[0]
+	fieldTypeName := strings.TrimPrefix(fieldLineParts[1], "*")
+	isArray := false
+
+	if strings.HasPrefix(fieldTypeName, "[]") {
+		fieldTypeName = strings.TrimPrefix(fieldTypeName[2:], "*")
+		isArray = true
+	}
+
+	isFile := fieldTypeName == "multipart.FileHeader"
+
+	if strings.Contains(fieldTypeName, ".") {
+		fieldParts := strings.Split(fieldTypeName, ".")
+		fieldTypeName = fieldParts[len(fieldParts)-1]
+	}
+
+	fieldType := ""
+	// float, int
+	switch {
+	case len(fieldTypeName) > 4 && fieldTypeName[0:5] == "float":
+		fieldType = "number"
+	case len(fieldTypeName) > 2 && fieldTypeName[0:3] == "int":
+		fieldType = "integer"
+	case fieldTypeName == "string":
+		fieldType = "string"
+	case fieldTypeName == "bool":
+		fieldType = "boolean"
+	case isFile:
+		fieldType = "file"
+	}
+
+	switch {
+	case isArray && len(fieldType) > 0:
+		field.Type = "array"
+		field.ItemsType = fieldType
+	case len(fieldType) > 0:
+		field.Type = fieldType
+	case isArray:
+		field.Type = "array"
+		field.Ref = fieldTypeName
+	default:
+		field.Type = "#object"
+		field.Ref = fieldTypeName
+	}
+
+	for _, validationTag := range []string{"binding", "validate"} {
+		if inArray("required", strings.Split(field.StructTag.Get(validationTag), ",")) {
+			field.Required = true
+		}
+	}
+
+	ok = true
+	return
+}
 
-			// fmt.Printf("FieldType: %s\n", fieldType)
+// QueryName returns the name a field is bound to in a query string or form,
+// based on its `form`, `query`, `schema` or `json` struct tag
+func (f ModelField) QueryName() string {
 
-			field := ModelField{
-				fieldLineParts[0],
-				fieldType,
-				fieldRef,
+	for _, key := range []string{"form", "query", "schema", "json"} {
+		if value, ok := f.StructTag.Lookup(key); ok {
+			name := strings.Split(value, ",")[0]
+			if len(name) > 0 {
+				return name
 			}
-			currentLine = currentLine + 1
-			model.Fields = append(model.Fields, field)
 		}
-		models[model.Name] = model
+	}
+
+	return f.Name
+}
 
+// JSONName returns the name a field is encoded with, based on its `json` struct tag
+func (f ModelField) JSONName() string {
+
+	if value, ok := f.StructTag.Lookup("json"); ok {
+		if name := strings.Split(value, ",")[0]; len(name) > 0 {
+			return name
+		}
+	}
+
+	return f.Name
+}
+
+// splitInlineTags splits a trailing field comment before each tag so the tags start their own line
+// Example: `The email @example jane@example.com` => [`The email`, `@example jane@example.com`]
+func splitInlineTags(comment string) (lines []string) {
+
+	for _, line := range strings.Split(comment, " @") {
+		if len(lines) > 0 {
+			line = "@" + line
+		}
+		lines = append(lines, line)
 	}
 
 	return
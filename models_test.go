@@ -38,6 +38,27 @@ func TestGetModels(t *testing.T) {
 	}
 }
 
+func TestGetModels_Description(t *testing.T) {
+
+	lines := []string{
+		"// @model User",
+		"// A user of the system",
+		"type User struct {",
+		"  Name string",
+		"}",
+	}
+
+	models, err := GetModels(lines, "some/file/path")
+
+	if _, ok := models["User"]; err != nil || !ok {
+		t.Fatalf("GetModels should have returned a map with key of `User` (actually %v, %v)", models, err)
+	}
+
+	if models["User"].Description != "A user of the system" {
+		t.Errorf("GetModels should have returned the description 'A user of the system' (actually '%s')", models["User"].Description)
+	}
+}
+
 func TestGetModels_ShouldReturnErrorIfLinesEmpty(t *testing.T) {
 
 	lines := []string{}
@@ -71,3 +92,112 @@ func TestGetModels_ShouldReturnErrorIfNoSymbolsFound(t *testing.T) {
 		t.Errorf("GetModels returned an error but it should have been %s (actually %s)", errString, err.Error())
 	}
 }
+
+func TestGetModels_FieldComments(t *testing.T) {
+
+	lines := []string{
+		"// @model ListUsersQuery",
+		"type ListUsersQuery struct {",
+		"	// The page number",
+		"	Page int `form:\"page\" binding:\"required\"`",
+		"	Status []string `form:\"status\"` // Filter by status",
+		"	BaseQuery",
+		"}",
+	}
+
+	models, _ := GetModels(lines, "some/file/path")
+	fields := models["ListUsersQuery"].Fields
+
+	if len(fields) != 3 {
+		t.Fatalf("GetModels should have returned a model with 3 fields (actually %d)", len(fields))
+	}
+
+	if fields[0].Description != "The page number" || !fields[0].Required {
+		t.Errorf("GetModels should have returned a required Page field described as '%s' (actually '%s')", "The page number", fields[0].Description)
+	}
+
+	if fields[1].Type != "array" || fields[1].ItemsType != "string" || fields[1].Description != "Filter by status" {
+		t.Errorf("GetModels should have returned a Status field of type array of string (actually %s of %s)", fields[1].Type, fields[1].ItemsType)
+	}
+
+	if !fields[2].Embedded || fields[2].Ref != "BaseQuery" {
+		t.Errorf("GetModels should have returned an embedded BaseQuery struct (actually %v)", fields[2])
+	}
+}
+
+func TestParseModelField(t *testing.T) {
+
+	field, ok := ParseModelField("Active *bool `json:\"active,omitempty\" validate:\"omitempty,required\"` // Whether the user is active")
+
+	if !ok {
+		t.Fatalf("ParseModelField should have parsed the field")
+	}
+
+	if field.Name != "Active" || field.Type != "boolean" {
+		t.Errorf("ParseModelField should have returned Active boolean (actually %s %s)", field.Name, field.Type)
+	}
+
+	if !field.Required {
+		t.Errorf("ParseModelField should have returned a required field")
+	}
+
+	if field.QueryName() != "active" {
+		t.Errorf("ModelField.QueryName should have returned '%s' (actually '%s')", "active", field.QueryName())
+	}
+
+	if field, ok := ParseModelField("*models.BaseModel"); !ok || !field.Embedded || field.Ref != "BaseModel" {
+		t.Errorf("ParseModelField should have returned an embedded BaseModel struct (actually %v)", field)
+	}
+
+	if _, ok := ParseModelField("}"); ok {
+		t.Errorf("ParseModelField should not have parsed a closing brace")
+	}
+}
+
+func TestModelFieldJSONName(t *testing.T) {
+
+	field, _ := ParseModelField("UserID int `json:\"user_id,omitempty\"`")
+
+	if field.JSONName() != "user_id" {
+		t.Errorf("ModelField.JSONName should have returned '%s' (actually '%s')", "user_id", field.JSONName())
+	}
+
+	field, _ = ParseModelField("Name string")
+
+	if field.JSONName() != "Name" {
+		t.Errorf("ModelField.JSONName should have returned '%s' (actually '%s')", "Name", field.JSONName())
+	}
+}
+
+func TestGetModels_FieldTags(t *testing.T) {
+
+	lines := []string{
+		"// @model User",
+		"// @example {\"email\": \"jane@example.com\"}",
+		"type User struct {",
+		"	// The email address",
+		"	// @format email",
+		"	Email string `json:\"email\"`",
+		"	Status string `json:\"status\"` // The status @enum active|disabled",
+		"}",
+	}
+
+	models, _ := GetModels(lines, "some/file/path")
+	model := models["User"]
+
+	if model.Example != "{\"email\": \"jane@example.com\"}" {
+		t.Errorf("GetModels should have returned the model example (actually '%s')", model.Example)
+	}
+
+	if len(model.Fields) != 2 {
+		t.Fatalf("GetModels should have returned a model with 2 fields (actually %d)", len(model.Fields))
+	}
+
+	if model.Fields[0].Description != "The email address" || model.Fields[0].Tags["format"][0] != "email" {
+		t.Errorf("GetModels should have returned an Email field with format email (actually '%s' %v)", model.Fields[0].Description, model.Fields[0].Tags)
+	}
+
+	if model.Fields[1].Description != "The status" || model.Fields[1].Tags["enum"][0] != "active|disabled" {
+		t.Errorf("GetModels should have returned a Status field with enum active|disabled (actually '%s' %v)", model.Fields[1].Description, model.Fields[1].Tags)
+	}
+}
@@ -0,0 +1,177 @@
+/**
+ * Modules
+ */
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleReplaceDirs returns the local directories that the `replace` directives of a go.mod file point to
+// Directives that replace a module with another module version are ignored. A missing go.mod file has no directives
+// Examples:
+//
+//	replace github.com/acme/models => ../models
+//	replace (
+//		github.com/acme/shared v1.2.0 => ./third_party/shared
+//	)
+func ModuleReplaceDirs(goModPath string) (dirs []string, err error) {
+
+	file, err := os.Open(goModPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	inBlock := false
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+
+		line := scanner.Text()
+		if commentIdx := strings.Index(line, "//"); commentIdx > -1 {
+			line = line[0:commentIdx]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case line == "replace (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "replace "):
+			line = strings.TrimSpace(line[len("replace "):])
+		case !inBlock:
+			continue
+		}
+
+		arrowIdx := strings.Index(line, "=>")
+		if arrowIdx < 0 {
+			continue
+		}
+
+		target := strings.Fields(line[arrowIdx+2:])
+		if len(target) != 1 || !isLocalModulePath(target[0]) {
+			continue
+		}
+
+		dir := target[0]
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(goModPath), dir)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	err = scanner.Err()
+	return
+}
+
+// isLocalModulePath checks if the target of a replace directive is a directory rather than a module path
+func isLocalModulePath(target string) bool {
+	return strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") || filepath.IsAbs(target)
+}
+
+// GoListFiles returns the go files of the packages matching `patterns` (e.g. `./...`), using `go list` in `dir`
+func GoListFiles(dir string, patterns []string) (files []string, err error) {
+
+	args := append([]string{"list", "-f", `{{$dir := .Dir}}{{range .GoFiles}}{{$dir}}/{{.}}{{"\n"}}{{end}}`}, patterns...)
+	command := exec.Command("go", args...)
+	command.Dir = dir
+
+	stderr := bytes.Buffer{}
+	command.Stderr = &stderr
+
+	output, err := command.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list %s failed in %s: %s %s", strings.Join(patterns, " "), dir, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			files = append(files, line)
+		}
+	}
+
+	return
+}
+
+// SourceFiles returns the files to scan in every source root: all the source files of the `Languages` in the root (see Sio.GetAllFilePaths),
+// or the files of the go packages matching `Packages` if it is set
+// Files that are in more than one root are only returned once
+func (s *Swaggerf) SourceFiles(rootPaths []string) (files []string, err error) {
+
+	seen := map[string]bool{}
+
+	languages := s.Languages
+	if len(languages) == 0 {
+		languages = DefaultLanguages
+	}
+
+	extensions, err := LanguageExtensions(languages)
+	if err != nil {
+		return
+	}
+
+	for _, rootPath := range rootPaths {
+
+		filter, filterErr := NewFileFilter(s.FS, rootPath, s.Include, s.Exclude)
+		if filterErr != nil {
+			return nil, filterErr
+		}
+
+		rootFiles := []string{}
+
+		if len(s.Packages) > 0 && !IsOSFS(s.FS) {
+			return nil, fmt.Errorf("-packages cannot be used with sources read from an archive or a git revision")
+		}
+
+		if len(s.Packages) > 0 {
+			packageFiles, listErr := GoListFiles(rootPath, s.Packages)
+			if listErr != nil {
+				return nil, listErr
+			}
+			// go list returns absolute paths
+			absRoot, absErr := filepath.Abs(rootPath)
+			if absErr != nil {
+				return nil, absErr
+			}
+			for _, filePath := range packageFiles {
+				relPath, relErr := filepath.Rel(absRoot, filePath)
+				if relErr != nil || strings.HasPrefix(relPath, "..") || !filter.ExcludedFile(filepath.ToSlash(relPath)) {
+					rootFiles = append(rootFiles, filePath)
+				}
+			}
+		} else {
+			sio := &Sio{Filter: filter, Extensions: extensions, FS: s.FS}
+			if err = sio.GetAllFilePaths(rootPath); err != nil {
+				return
+			}
+			rootFiles = sio.TmpFiles
+		}
+
+		for _, filePath := range rootFiles {
+			key, absErr := filepath.Abs(filePath)
+			if absErr != nil {
+				key = filepath.Clean(filePath)
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			files = append(files, filePath)
+		}
+	}
+
+	return
+}
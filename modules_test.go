@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModuleReplaceDirs(t *testing.T) {
+
+	rootPath, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	goMod := strings.Join([]string{
+		"module github.com/acme/api",
+		"",
+		"replace github.com/acme/models => ../models // shared models",
+		"replace github.com/acme/fork => github.com/someone/fork v1.0.0",
+		"",
+		"replace (",
+		"	github.com/acme/shared v1.2.0 => ./third_party/shared",
+		"	github.com/acme/abs => /src/abs",
+		")",
+	}, "\n")
+	ioutil.WriteFile(filepath.Join(rootPath, "go.mod"), []byte(goMod), 0644)
+
+	dirs, err := ModuleReplaceDirs(filepath.Join(rootPath, "go.mod"))
+
+	if err != nil {
+		t.Errorf("ModuleReplaceDirs should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	expected := []string{
+		filepath.Join(filepath.Dir(rootPath), "models"),
+		filepath.Join(rootPath, "third_party", "shared"),
+		"/src/abs",
+	}
+
+	if strings.Join(dirs, ",") != strings.Join(expected, ",") {
+		t.Errorf("ModuleReplaceDirs should have returned %v (actually %v)", expected, dirs)
+	}
+
+	if dirs, err := ModuleReplaceDirs(filepath.Join(rootPath, "missing", "go.mod")); err != nil || len(dirs) != 0 {
+		t.Errorf("ModuleReplaceDirs should have returned no directories for a missing go.mod file (actually %v, %v)", dirs, err)
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+
+	rootPath, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	for _, filePath := range []string{"go.mod", "cmd/api/main.go", "pkg/models/user.go", "pkg/models/mocks/user.go"} {
+		os.MkdirAll(filepath.Join(rootPath, filepath.Dir(filePath)), 0755)
+		ioutil.WriteFile(filepath.Join(rootPath, filePath), []byte("package main\n"), 0644)
+	}
+	ioutil.WriteFile(filepath.Join(rootPath, "go.mod"), []byte("module example.com/api\n"), 0644)
+
+	s := Swaggerf{Exclude: []string{"mocks/"}}
+	files, err := s.SourceFiles([]string{filepath.Join(rootPath, "cmd"), filepath.Join(rootPath, "pkg"), rootPath + "/pkg/models"})
+
+	if err != nil {
+		t.Fatalf("SourceFiles should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if len(files) != 2 || !strings.HasSuffix(files[0], "cmd/api/main.go") || !strings.HasSuffix(files[1], "pkg/models/user.go") {
+		t.Errorf("SourceFiles should have returned main.go and user.go once (actually %v)", files)
+	}
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	s.Packages = []string{"./pkg/..."}
+	files, err = s.SourceFiles([]string{rootPath})
+
+	if err != nil {
+		t.Fatalf("SourceFiles should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if len(files) != 1 || !strings.HasSuffix(files[0], "pkg/models/user.go") {
+		t.Errorf("SourceFiles should have returned the files of the packages matching ./pkg/... (actually %v)", files)
+	}
+
+	// Files are excluded from a relative source directory too
+	workingDir, _ := os.Getwd()
+	os.Chdir(rootPath)
+	defer os.Chdir(workingDir)
+
+	files, err = s.SourceFiles([]string{"."})
+
+	if err != nil || len(files) != 1 || !strings.HasSuffix(files[0], "pkg/models/user.go") {
+		t.Errorf("SourceFiles should have returned the files of the packages matching ./pkg/... from a relative source directory (actually %v, %v)", files, err)
+	}
+}
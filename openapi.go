@@ -0,0 +1,539 @@
+/**
+ * OpenAPI
+ */
+package main
+
+import (
+	"strings"
+)
+
+// OpenAPIVersion is the version of the OpenAPI documents the swagger spec is converted to (see ConvertOpenAPI)
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPI is an OpenAPI 3 document
+type OpenAPI struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       SwaggerInfo                            `json:"info"`
+	Servers    []OpenAPIServer                        `json:"servers,omitempty"`
+	Tags       []Tag                                  `json:"tags,omitempty"`
+	Paths      map[string]map[string]OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                      `json:"components"`
+	Security   []SecurityRequirement                  `json:"security,omitempty"`
+}
+
+// OpenAPIServer is the url the api is served from (scheme, host and base path of the swagger spec)
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+// OpenAPIComponents are the reusable objects of an OpenAPI document
+type OpenAPIComponents struct {
+	Schemas         map[string]ModelDefinition  `json:"schemas"`
+	Parameters      map[string]OpenAPIParameter `json:"parameters,omitempty"`
+	Responses       map[string]OpenAPIResponse  `json:"responses,omitempty"`
+	SecuritySchemes map[string]interface{}      `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPIOperation is an operation of an OpenAPI document
+type OpenAPIOperation struct {
+	Description string                     `json:"description"`
+	Summary     string                     `json:"summary"`
+	OperationID string                     `json:"operationId,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	Security    *[]SecurityRequirement     `json:"security,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	XSunset     string                     `json:"x-sunset,omitempty"`
+	Extensions  map[string]interface{}     `json:"-"` // vendor extensions (@x-...)
+}
+
+// OpenAPIParameter is a path, query or header parameter of an OpenAPI operation
+type OpenAPIParameter struct {
+	Ref         string         `json:"$ref,omitempty"`
+	In          string         `json:"in,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Style       string         `json:"style,omitempty"`
+	Explode     *bool          `json:"explode,omitempty"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIRequestBody is the body of an OpenAPI operation, by mime type
+type OpenAPIRequestBody struct {
+	Description string                      `json:"description,omitempty"`
+	Required    bool                        `json:"required,omitempty"`
+	Content     map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response of an OpenAPI operation
+type OpenAPIResponse struct {
+	Ref         string                      `json:"$ref,omitempty"`
+	Description string                      `json:"description"`
+	Headers     map[string]OpenAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIHeader is a header of an OpenAPI response
+type OpenAPIHeader struct {
+	Description string         `json:"description,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIMediaType is the schema and examples of a request body or response in a mime type
+type OpenAPIMediaType struct {
+	Schema   *OpenAPISchema            `json:"schema,omitempty"`
+	Examples map[string]OpenAPIExample `json:"examples,omitempty"`
+}
+
+// OpenAPIExample is an example of a request body or response
+type OpenAPIExample struct {
+	Value interface{} `json:"value"`
+}
+
+// OpenAPISchema is the schema of a parameter, header, request body or response
+type OpenAPISchema struct {
+	Ref         string                   `json:"$ref,omitempty"`
+	Type        string                   `json:"type,omitempty"`
+	Format      string                   `json:"format,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Items       *OpenAPISchema           `json:"items,omitempty"`
+	Properties  map[string]OpenAPISchema `json:"properties,omitempty"`
+	Required    []string                 `json:"required,omitempty"`
+	Enum        []interface{}            `json:"enum,omitempty"`
+	Default     interface{}              `json:"default,omitempty"`
+	Minimum     *float64                 `json:"minimum,omitempty"`
+	Maximum     *float64                 `json:"maximum,omitempty"`
+	MinLength   *int                     `json:"minLength,omitempty"`
+	MaxLength   *int                     `json:"maxLength,omitempty"`
+	MinItems    *int                     `json:"minItems,omitempty"`
+	MaxItems    *int                     `json:"maxItems,omitempty"`
+	Pattern     string                   `json:"pattern,omitempty"`
+}
+
+// openAPIRefs are the prefixes of swagger references and of the OpenAPI components they point to
+var openAPIRefs = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/parameters/":  "#/components/parameters/",
+	"#/responses/":   "#/components/responses/",
+}
+
+// openAPIFlows are the OpenAPI names of the swagger oauth2 flows
+var openAPIFlows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// ConvertOpenAPI converts a swagger spec into an OpenAPI 3 document
+// Body and form data parameters become the request body of their operation (form data as a `multipart/form-data`
+// or `application/x-www-form-urlencoded` object schema), and definitions become the schemas of the components
+func ConvertOpenAPI(swagger Swagger) OpenAPI {
+
+	openAPI := OpenAPI{
+		OpenAPI:  OpenAPIVersion,
+		Info:     swagger.Info,
+		Servers:  openAPIServers(swagger),
+		Tags:     swagger.Tags,
+		Paths:    map[string]map[string]OpenAPIOperation{},
+		Security: swagger.Security,
+		Components: OpenAPIComponents{
+			Schemas:         map[string]ModelDefinition{},
+			SecuritySchemes: openAPISecuritySchemes(swagger.SecurityDefinitions),
+		},
+	}
+
+	for name, definition := range swagger.Definitions {
+		openAPI.Components.Schemas[name] = openAPIDefinition(definition)
+	}
+
+	// Reusable body and form data parameters are not parameters in OpenAPI, and are added to the request bodies instead
+	for name, parameter := range swagger.Parameters {
+		if parameter.In == TransportBody || parameter.In == TransportFormData {
+			continue
+		}
+		if openAPI.Components.Parameters == nil {
+			openAPI.Components.Parameters = map[string]OpenAPIParameter{}
+		}
+		openAPI.Components.Parameters[name] = openAPIParameter(parameter)
+	}
+
+	for name, response := range swagger.Responses {
+		if openAPI.Components.Responses == nil {
+			openAPI.Components.Responses = map[string]OpenAPIResponse{}
+		}
+		openAPI.Components.Responses[name] = openAPIResponse(name, response, openAPIMimeTypes(nil, swagger.Produces))
+	}
+
+	for pathName, operations := range swagger.Paths {
+		openAPI.Paths[pathName] = map[string]OpenAPIOperation{}
+		for verb, path := range operations {
+			openAPI.Paths[pathName][verb] = openAPIOperation(path, swagger)
+		}
+	}
+
+	return openAPI
+}
+
+// openAPIOperation converts a swagger operation
+func openAPIOperation(path Path, swagger Swagger) OpenAPIOperation {
+
+	operation := OpenAPIOperation{
+		Description: path.Description,
+		Summary:     path.Summary,
+		OperationID: path.OperationID,
+		Tags:        path.Tags,
+		Security:    path.Security,
+		Deprecated:  path.Deprecated,
+		XSunset:     path.XSunset,
+		Extensions:  path.Extensions,
+		Responses:   map[string]OpenAPIResponse{},
+	}
+
+	consumes := openAPIMimeTypes(path.Consumes, swagger.Consumes)
+	produces := openAPIMimeTypes(path.Produces, swagger.Produces)
+
+	formParameters := []Parameter{}
+
+	for _, parameter := range path.Parameters {
+
+		if reusable, ok := swagger.Parameters[strings.TrimPrefix(parameter.Ref, "#/parameters/")]; ok && len(parameter.Ref) > 0 {
+			if reusable.In == TransportBody || reusable.In == TransportFormData {
+				parameter = reusable
+			}
+		}
+
+		switch parameter.In {
+		case TransportBody:
+			operation.RequestBody = openAPIRequestBody(parameter, consumes)
+		case TransportFormData:
+			formParameters = append(formParameters, parameter)
+		default:
+			operation.Parameters = append(operation.Parameters, openAPIParameter(parameter))
+		}
+	}
+
+	if len(formParameters) > 0 {
+		operation.RequestBody = openAPIFormRequestBody(formParameters, consumes)
+	}
+
+	for key, response := range path.Responses {
+		operation.Responses[key] = openAPIResponse(key, response, produces)
+	}
+
+	return operation
+}
+
+// openAPIMimeTypes returns the mime types of an operation, which default to the global ones (or `application/json`)
+func openAPIMimeTypes(operationMimeTypes []string, globalMimeTypes []string) []string {
+
+	if len(operationMimeTypes) > 0 {
+		return operationMimeTypes
+	}
+
+	if len(globalMimeTypes) > 0 {
+		return globalMimeTypes
+	}
+
+	return []string{MimeTypeJSON}
+}
+
+// openAPIParameter converts a swagger path, query or header parameter
+func openAPIParameter(parameter Parameter) OpenAPIParameter {
+
+	if len(parameter.Ref) > 0 {
+		return OpenAPIParameter{Ref: openAPIRef(parameter.Ref)}
+	}
+
+	converted := OpenAPIParameter{
+		In:          parameter.In,
+		Name:        parameter.Name,
+		Description: parameter.Description,
+		Required:    parameter.Required || parameter.In == TransportPath,
+		Schema:      openAPIParameterSchema(parameter),
+	}
+
+	// Collection formats become styles. csv is already the default of path and header params
+	explode := false
+	switch parameter.CollectionFormat {
+	case "csv":
+		if parameter.In == TransportQuery {
+			converted.Style = "form"
+			converted.Explode = &explode
+		}
+	case "ssv":
+		converted.Style = "spaceDelimited"
+		converted.Explode = &explode
+	case "pipes":
+		converted.Style = "pipeDelimited"
+		converted.Explode = &explode
+	case "multi":
+		explode = true
+		converted.Style = "form"
+		converted.Explode = &explode
+	}
+
+	return converted
+}
+
+// openAPIParameterSchema returns the schema of a swagger parameter that is not a model
+func openAPIParameterSchema(parameter Parameter) *OpenAPISchema {
+
+	schema := &OpenAPISchema{
+		Enum:      parameter.Enum,
+		Default:   parameter.Default,
+		Minimum:   parameter.Minimum,
+		Maximum:   parameter.Maximum,
+		MinLength: parameter.MinLength,
+		MaxLength: parameter.MaxLength,
+		MinItems:  parameter.MinItems,
+		MaxItems:  parameter.MaxItems,
+		Pattern:   parameter.Pattern,
+	}
+	schema.Type, schema.Format = openAPIType(parameter.Type, parameter.Format)
+
+	if parameter.Items != nil {
+		schema.Items = &OpenAPISchema{Enum: parameter.Items.Enum}
+		schema.Items.Type, schema.Items.Format = openAPIType(parameter.Items.Type, parameter.Items.Format)
+	}
+
+	return schema
+}
+
+// openAPIType converts a swagger type. Files are binary strings
+func openAPIType(swaggerType string, format string) (string, string) {
+
+	if swaggerType == SwaggerTypeFile {
+		return SwaggerTypeString, "binary"
+	}
+
+	return swaggerType, format
+}
+
+// openAPIRequestBody converts a swagger body parameter into a request body in every mime type the operation consumes
+func openAPIRequestBody(parameter Parameter, consumes []string) *OpenAPIRequestBody {
+
+	body := &OpenAPIRequestBody{
+		Description: parameter.Description,
+		Required:    parameter.Required,
+		Content:     map[string]OpenAPIMediaType{},
+	}
+
+	schema := openAPIParameterSchema(parameter)
+	if ref, ok := parameter.Schema["$ref"]; ok {
+		schema = &OpenAPISchema{Ref: openAPIRef(ref)}
+	}
+
+	// The example of the body (@example body) is named `body`
+	mediaType := OpenAPIMediaType{Schema: schema}
+	if parameter.XExample != nil {
+		mediaType.Examples = map[string]OpenAPIExample{TagArgExampleBody: {parameter.XExample}}
+	}
+
+	for _, mimeType := range consumes {
+		body.Content[mimeType] = mediaType
+	}
+
+	return body
+}
+
+// openAPIFormRequestBody converts swagger form data parameters into a request body with an object schema,
+// sent as `multipart/form-data` or `application/x-www-form-urlencoded`
+func openAPIFormRequestBody(parameters []Parameter, consumes []string) *OpenAPIRequestBody {
+
+	schema := &OpenAPISchema{Type: "object", Properties: map[string]OpenAPISchema{}}
+
+	for _, parameter := range parameters {
+		property := openAPIParameterSchema(parameter)
+		property.Description = parameter.Description
+		schema.Properties[parameter.Name] = *property
+		if parameter.Required {
+			schema.Required = append(schema.Required, parameter.Name)
+		}
+	}
+
+	body := &OpenAPIRequestBody{
+		Required: len(schema.Required) > 0,
+		Content:  map[string]OpenAPIMediaType{},
+	}
+
+	for _, mimeType := range consumes {
+		if mimeType == MimeTypeMultipart || mimeType == MimeTypeFormURLEncoded {
+			body.Content[mimeType] = OpenAPIMediaType{Schema: schema}
+		}
+	}
+
+	if len(body.Content) == 0 {
+		body.Content[MimeTypeFormURLEncoded] = OpenAPIMediaType{Schema: schema}
+	}
+
+	return body
+}
+
+// openAPIResponse converts a swagger response, whose schema is listed for every mime type the operation produces
+// Its examples are named after the response `key` (e.g. `200`)
+func openAPIResponse(key string, response PathResponse, produces []string) OpenAPIResponse {
+
+	if len(response.Ref) > 0 {
+		return OpenAPIResponse{Ref: openAPIRef(response.Ref)}
+	}
+
+	converted := OpenAPIResponse{Description: response.Description}
+
+	for name, header := range response.Headers {
+		if converted.Headers == nil {
+			converted.Headers = map[string]OpenAPIHeader{}
+		}
+		converted.Headers[name] = OpenAPIHeader{
+			Description: header.Description,
+			Schema:      &OpenAPISchema{Type: header.Type},
+		}
+		if header.Items != nil {
+			converted.Headers[name].Schema.Items = &OpenAPISchema{Type: header.Items.Type}
+		}
+	}
+
+	schema := &OpenAPISchema{Type: response.Schema.Type, Ref: openAPIRef(response.Schema.Ref)}
+	if len(response.Schema.Items) > 0 {
+		schema.Items = &OpenAPISchema{Type: response.Schema.Items["type"], Ref: openAPIRef(response.Schema.Items["$ref"])}
+	}
+
+	if len(schema.Type) > 0 || len(schema.Ref) > 0 {
+		converted.Content = map[string]OpenAPIMediaType{}
+		for _, mimeType := range produces {
+			converted.Content[mimeType] = OpenAPIMediaType{Schema: schema}
+		}
+	}
+
+	for mimeType, example := range response.Examples {
+		if converted.Content == nil {
+			converted.Content = map[string]OpenAPIMediaType{}
+		}
+		mediaType := converted.Content[mimeType]
+		mediaType.Examples = map[string]OpenAPIExample{key: {example}}
+		converted.Content[mimeType] = mediaType
+	}
+
+	return converted
+}
+
+// openAPIDefinition converts the references and file properties of a definition
+func openAPIDefinition(definition ModelDefinition) ModelDefinition {
+
+	properties := map[string]Property{}
+
+	for name, property := range definition.Properties {
+
+		property.Ref = openAPIRef(property.Ref)
+		property.Type, property.Format = openAPIType(property.Type, property.Format)
+
+		if len(property.Items) > 0 {
+			items := map[string]string{}
+			for key, value := range property.Items {
+				items[key] = value
+			}
+			if ref, ok := items["$ref"]; ok {
+				items["$ref"] = openAPIRef(ref)
+			}
+			if items["type"] == SwaggerTypeFile {
+				items["type"], items["format"] = openAPIType(items["type"], items["format"])
+			}
+			property.Items = items
+		}
+
+		properties[name] = property
+	}
+
+	definition.Properties = properties
+
+	return definition
+}
+
+// openAPIRef converts a swagger reference (e.g. `#/definitions/User`) to the OpenAPI component it points to
+func openAPIRef(ref string) string {
+
+	for swaggerPrefix, openAPIPrefix := range openAPIRefs {
+		if strings.HasPrefix(ref, swaggerPrefix) {
+			return openAPIPrefix + ref[len(swaggerPrefix):]
+		}
+	}
+
+	return ref
+}
+
+// openAPIServers returns the urls of the schemes, host and base path of a swagger spec
+func openAPIServers(swagger Swagger) (servers []OpenAPIServer) {
+
+	if len(swagger.Host) == 0 {
+		if len(swagger.BasePath) > 0 {
+			servers = append(servers, OpenAPIServer{swagger.BasePath})
+		}
+		return
+	}
+
+	schemes := swagger.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	for _, scheme := range schemes {
+		servers = append(servers, OpenAPIServer{scheme + "://" + swagger.Host + swagger.BasePath})
+	}
+
+	return
+}
+
+// openAPISecuritySchemes converts swagger security definitions
+// Basic authentication becomes an http scheme, and the flow of oauth2 schemes is listed in `flows`
+func openAPISecuritySchemes(definitions map[string]interface{}) map[string]interface{} {
+
+	if len(definitions) == 0 {
+		return nil
+	}
+
+	schemes := map[string]interface{}{}
+
+	for name, definition := range definitions {
+
+		definitionMap, ok := definition.(map[string]interface{})
+		if !ok {
+			schemes[name] = definition
+			continue
+		}
+
+		scheme := map[string]interface{}{}
+		for key, value := range definitionMap {
+			if key == "description" || IsVendorExtension(key) {
+				scheme[key] = value
+			}
+		}
+
+		switch definitionMap["type"] {
+		case "basic":
+			scheme["type"] = "http"
+			scheme["scheme"] = "basic"
+		case "oauth2":
+			flow := map[string]interface{}{"scopes": map[string]interface{}{}}
+			for _, key := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+				if value, ok := definitionMap[key]; ok {
+					flow[key] = value
+				}
+			}
+			flowName, _ := definitionMap["flow"].(string)
+			if converted, ok := openAPIFlows[flowName]; ok {
+				flowName = converted
+			}
+			scheme["type"] = "oauth2"
+			scheme["flows"] = map[string]interface{}{flowName: flow}
+		default:
+			scheme = definitionMap
+		}
+
+		schemes[name] = scheme
+	}
+
+	return schemes
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConvertOpenAPI(t *testing.T) {
+
+	swagger := Swagger{
+		Swagger:  "2.0",
+		Host:     "myhost.com",
+		BasePath: "/v1",
+		Schemes:  []string{"https"},
+		Consumes: []string{MimeTypeJSON},
+		Produces: []string{MimeTypeJSON},
+		Paths: map[string]map[string]Path{
+			"/users/{id}/avatar": {
+				"put": Path{
+					OperationID: "PutAvatar",
+					Consumes:    []string{MimeTypeMultipart},
+					Parameters: []Parameter{
+						{In: TransportPath, Name: "id", Type: "integer"},
+						{In: TransportFormData, Name: "avatar", Type: SwaggerTypeFile, Required: true},
+						{In: TransportFormData, Name: "caption", Type: "string", Description: "A caption"},
+						{Ref: "#/parameters/RequestID"},
+					},
+					Responses: map[string]PathResponse{
+						"200": {Description: "OK", Schema: PathSchema{Ref: "#/definitions/User"}, Examples: map[string]interface{}{MimeTypeJSON: map[string]interface{}{"Avatar": "jane.png"}}},
+						"401": {Ref: "#/responses/Unauthorized"},
+					},
+				},
+			},
+			"/users": {
+				"post": Path{
+					Parameters: []Parameter{
+						{In: TransportBody, Name: "user", Schema: map[string]string{"$ref": "#/definitions/User"}, XExample: map[string]interface{}{"Avatar": "jane.png"}},
+						{In: TransportQuery, Name: "ids", Type: "array", Items: &ParameterItems{Type: "integer"}, CollectionFormat: "multi"},
+					},
+					Responses: map[string]PathResponse{},
+				},
+			},
+		},
+		Definitions: map[string]ModelDefinition{
+			"User": {Type: "object", Properties: map[string]Property{
+				"Groups": {Type: "array", Items: map[string]string{"$ref": "#/definitions/Group"}},
+				"Avatar": {Type: SwaggerTypeFile},
+			}},
+		},
+		Parameters: map[string]Parameter{"RequestID": {In: TransportHeader, Name: "X-Request-ID", Type: "string"}},
+		Responses:  map[string]PathResponse{"Unauthorized": {Description: "Unauthorized"}},
+		SecurityDefinitions: map[string]interface{}{
+			"oauth": map[string]interface{}{"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://myhost.com/auth", "tokenUrl": "https://myhost.com/token"},
+			"basic": map[string]interface{}{"type": "basic"},
+		},
+	}
+
+	openAPI := ConvertOpenAPI(swagger)
+
+	if openAPI.OpenAPI != OpenAPIVersion || len(openAPI.Servers) != 1 || openAPI.Servers[0].URL != "https://myhost.com/v1" {
+		t.Errorf("ConvertOpenAPI should have returned an OpenAPI %s document served from https://myhost.com/v1 (actually %s, %v)", OpenAPIVersion, openAPI.OpenAPI, openAPI.Servers)
+	}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"the parameters of PutAvatar", openAPI.Paths["/users/{id}/avatar"]["put"].Parameters, `[{"in":"path","name":"id","required":true,"schema":{"type":"integer"}},{"$ref":"#/components/parameters/RequestID"}]`},
+		{"the multipart request body of PutAvatar", openAPI.Paths["/users/{id}/avatar"]["put"].RequestBody, `{"required":true,"content":{"multipart/form-data":{"schema":{"type":"object","properties":{"avatar":{"type":"string","format":"binary"},"caption":{"type":"string","description":"A caption"}},"required":["avatar"]}}}}`},
+		{"the responses of PutAvatar", openAPI.Paths["/users/{id}/avatar"]["put"].Responses, `{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"},"examples":{"200":{"value":{"Avatar":"jane.png"}}}}}},"401":{"$ref":"#/components/responses/Unauthorized","description":""}}`},
+		{"the request body of the post", openAPI.Paths["/users"]["post"].RequestBody, `{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"},"examples":{"body":{"value":{"Avatar":"jane.png"}}}}}}`},
+		{"the parameters of the post", openAPI.Paths["/users"]["post"].Parameters, `[{"in":"query","name":"ids","style":"form","explode":true,"schema":{"type":"array","items":{"type":"integer"}}}]`},
+		{"the User schema", openAPI.Components.Schemas["User"], `{"type":"object","properties":{"Avatar":{"type":"string","format":"binary"},"Groups":{"type":"array","items":{"$ref":"#/components/schemas/Group"}}}}`},
+		{"the security schemes", openAPI.Components.SecuritySchemes, `{"basic":{"scheme":"basic","type":"http"},"oauth":{"flows":{"authorizationCode":{"authorizationUrl":"https://myhost.com/auth","scopes":{},"tokenUrl":"https://myhost.com/token"}},"type":"oauth2"}}`},
+	}
+
+	for _, test := range tests {
+		if actual, _ := json.Marshal(test.value); string(actual) != test.expected {
+			t.Errorf("ConvertOpenAPI should have converted %s to %s (actually %s)", test.name, test.expected, actual)
+		}
+	}
+}
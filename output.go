@@ -0,0 +1,86 @@
+/**
+ * Output
+ */
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StdoutPath is the -out path that writes the swagger spec to stdout
+const StdoutPath = "-"
+
+// OutputFormats are the output formats, by file extension
+var OutputFormats = map[string]string{
+	".json": "json",
+	".yaml": "yaml",
+	".yml":  "yaml",
+}
+
+// Output is a file the swagger spec is written to
+type Output struct {
+	Path   string // StdoutPath for stdout
+	Format string // json | yaml
+}
+
+// ParseOutput returns the output of a -out path, whose format is given by its extension
+// Stdout (`-`) uses `defaultFormat`
+// Examples: `api/openapi.yaml`, `swagger.json`, `-`
+func ParseOutput(outPath string, defaultFormat string) (output Output, err error) {
+
+	if outPath == StdoutPath {
+		return Output{StdoutPath, defaultFormat}, nil
+	}
+
+	format, ok := OutputFormats[strings.ToLower(filepath.Ext(outPath))]
+	if !ok {
+		return output, fmt.Errorf("The output %s should have a .json, .yaml or .yml extension", outPath)
+	}
+
+	return Output{outPath, format}, nil
+}
+
+// WriteOutputs writes the swagger spec (or OpenAPI document) to every output, creating the directories they are in
+// Files are replaced atomically (see WriteFileAtomic), so readers never see a partially written spec
+func WriteOutputs(spec interface{}, outputs []Output) error {
+
+	encoded := map[string][]byte{}
+
+	for _, output := range outputs {
+
+		data, ok := encoded[output.Format]
+		if !ok {
+			var err error
+			if data, err = MarshalSwagger(spec, output.Format); err != nil {
+				return err
+			}
+			encoded[output.Format] = data
+		}
+
+		if output.Path == StdoutPath {
+			if !strings.HasSuffix(string(data), "\n") {
+				data = append(data, '\n')
+			}
+			if _, err := os.Stdout.Write(data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(output.Path), 0755); err != nil {
+			return err
+		}
+
+		log.Printf("Writing swagger definition to %s", output.Path)
+
+		if err := WriteFileAtomic(output.Path, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseOutput(t *testing.T) {
+
+	expected := map[string]Output{
+		"api/openapi.yaml": {"api/openapi.yaml", "yaml"},
+		"swagger.YML":      {"swagger.YML", "yaml"},
+		"out/swagger.json": {"out/swagger.json", "json"},
+		"-":                {"-", "yaml"},
+	}
+
+	for outPath, expectedOutput := range expected {
+		if output, err := ParseOutput(outPath, "yaml"); err != nil || output != expectedOutput {
+			t.Errorf("ParseOutput should have returned %v for %s (actually %v, %v)", expectedOutput, outPath, output, err)
+		}
+	}
+
+	if _, err := ParseOutput("swagger.txt", "json"); err == nil {
+		t.Errorf("ParseOutput should have returned an error for an unknown extension")
+	}
+}
+
+func TestWriteOutputs(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	swagger := Swagger{Swagger: "2.0"}
+	jsonPath := filepath.Join(dir, "docs", "api", "swagger.json")
+	yamlPath := filepath.Join(dir, "openapi.yaml")
+
+	if err = WriteOutputs(swagger, []Output{{jsonPath, "json"}, {yamlPath, "yaml"}}); err != nil {
+		t.Fatalf("WriteOutputs should have returned a nil error (actually %s)", err.Error())
+	}
+
+	if content, _ := ioutil.ReadFile(jsonPath); !strings.Contains(string(content), `"swagger": "2.0"`) {
+		t.Errorf("WriteOutputs should have written json to %s, creating its directory (actually '%s')", jsonPath, content)
+	}
+
+	if content, _ := ioutil.ReadFile(yamlPath); !strings.Contains(string(content), `swagger: "2.0"`) {
+		t.Errorf("WriteOutputs should have written yaml to %s (actually '%s')", yamlPath, content)
+	}
+
+	// Only the outputs are left in the directory (no temporary files)
+	if files, _ := ioutil.ReadDir(dir); len(files) != 2 {
+		t.Errorf("WriteOutputs should have left 2 entries in the directory (actually %d)", len(files))
+	}
+}
@@ -0,0 +1,223 @@
+/**
+ * Parse
+ */
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// reportFunc reports a diagnostic, with the same arguments as log.Printf
+type reportFunc func(format string, v ...interface{})
+
+// symbolStrings are the strings that mark the symbols ScanSymbols looks for, by tag name
+var symbolStrings = []struct {
+	Tag    string
+	Symbol string
+}{
+	{TagRoute, "@" + TagRoute},
+	{TagModel, "@" + TagModel + " "},
+	{TagParamDef, "@" + TagParamDef},
+	{TagResponseDef, "@" + TagResponseDef},
+	{TagTagDef, "@" + TagTagDef},
+}
+
+// ScanSymbols finds the routes, models, reusables and tag definitions of `lines` in a single pass,
+// returning their symbols by tag name
+func ScanSymbols(lines []string) (symbols map[string][]Symbol) {
+
+	symbols = map[string][]Symbol{}
+
+	for lineNum, line := range lines {
+
+		if !strings.Contains(line, "@") {
+			continue
+		}
+
+		for _, symbolString := range symbolStrings {
+			if strings.Contains(line, symbolString.Symbol) {
+				symbols[symbolString.Tag] = append(symbols[symbolString.Tag], Symbol{
+					SymbolString: symbolString.Symbol,
+					LineNum:      lineNum,
+					Line:         line,
+					Comments:     []string{},
+					Tags:         map[string][]string{},
+					Type:         symbolString.Tag,
+				})
+			}
+		}
+	}
+
+	return
+}
+
+// FileResult is everything parsed from a single source file
+type FileResult struct {
+	FilePath     string
+	Routes       map[string][]Route
+	Models       map[string]Model
+	RouterRoutes []RouterRoute
+	TagDefs      []TagDef
+	Reusables    Reusables
+	Diagnostics  []string // errors and warnings found while parsing, in the order they were found
+	Hash         string   // sha256 of the file's content (see Cache)
+	Cached       bool     `json:"-"` // set if the result was reused from the cache
+	Err          error    `json:"-"` // set if the file could not be read
+}
+
+// report records a diagnostic so it can be logged once all files are parsed
+func (r *FileResult) report(format string, v ...interface{}) {
+	r.Diagnostics = append(r.Diagnostics, fmt.Sprintf(format, v...))
+}
+
+// ParseFile reads a source file from `fsys` (the OS filesystem if nil) and parses it,
+// unless `cache` holds the result for its current content
+// Router registrations are only looked for if `discover` is set
+func ParseFile(fsys fs.FS, filePath string, discover bool, cache *Cache) (result FileResult) {
+
+	result.FilePath = filePath
+
+	if fsys == nil {
+		fsys = osFS{}
+	}
+
+	content, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		result.Err = err
+		return
+	}
+
+	hash := HashContent(content)
+
+	if cached, ok := cache.Lookup(filePath, hash, discover); ok {
+		cached.Cached = true
+		return cached
+	}
+
+	result = ParseLines(splitLines(content), filePath, discover)
+	result.Hash = hash
+
+	return
+}
+
+// ParseLines parses the lines of a source file, in the language of its extension (see LanguageForFile)
+func ParseLines(lines []string, filePath string, discover bool) (result FileResult) {
+
+	result.FilePath = filePath
+
+	language := LanguageForFile(filePath)
+	commentLines := extractComments(lines, language.CommentSyntax())
+	symbols := commentSymbols(ScanSymbols(lines), commentLines)
+
+	result.Routes = getRoutes(language, lines, commentLines, filePath, symbols[TagRoute], result.report)
+	result.Models = getModels(language, lines, commentLines, filePath, symbols[TagModel], result.report)
+	result.Reusables = getReusables(filePath, append(symbols[TagParamDef], symbols[TagResponseDef]...), result.report)
+	result.TagDefs = getTagDefs(filePath, symbols[TagTagDef], result.report)
+
+	// Router registrations are only looked for in go files
+	if discover && language.Name() == (GoLanguage{}).Name() {
+		result.RouterRoutes = GetRouterRoutes(lines, filePath)
+	}
+
+	return
+}
+
+// commentSymbols keeps the symbols found in comments, leaving out code that looks like a tag
+// (e.g. the python decorator `@router.get("/users")`)
+func commentSymbols(symbols map[string][]Symbol, commentLines []CommentLine) map[string][]Symbol {
+
+	for tag, tagSymbols := range symbols {
+		kept := []Symbol{}
+		for _, symbol := range tagSymbols {
+			if commentLines[symbol.LineNum].IsComment {
+				kept = append(kept, symbol)
+			}
+		}
+		symbols[tag] = kept
+	}
+
+	return symbols
+}
+
+// ParseFiles parses `filePaths` of `fsys` with a pool of `workers` goroutines (one per CPU if `workers` is not positive),
+// reusing the results in `cache` (which can be nil) for unchanged files
+// The results are in the same order as `filePaths`, regardless of the order the files are parsed in
+func ParseFiles(fsys fs.FS, filePaths []string, discover bool, workers int, cache *Cache) (results []FileResult) {
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results = make([]FileResult, len(filePaths))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = ParseFile(fsys, filePaths[index], discover, cache)
+			}
+		}()
+	}
+
+	for index := range filePaths {
+		indexes <- index
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return
+}
+
+// mergeFileResults combines the results of every file, in order, logging their diagnostics
+// When a model or reusable is declared more than once, the last declaration wins
+func mergeFileResults(results []FileResult) (routes map[string][]Route, models map[string]Model, routerRoutes []RouterRoute, tagDefs []TagDef, reusables Reusables, err error) {
+
+	routes = map[string][]Route{}
+	models = map[string]Model{}
+	reusables = Reusables{
+		Params:    map[string]Param{},
+		Responses: map[string]Response{},
+	}
+
+	for _, result := range results {
+
+		if result.Err != nil {
+			err = result.Err
+			return
+		}
+
+		for _, diagnostic := range result.Diagnostics {
+			log.Print(diagnostic)
+		}
+
+		for path, pathRoutes := range result.Routes {
+			routes[path] = append(routes[path], pathRoutes...)
+		}
+
+		for name, model := range result.Models {
+			models[name] = model
+		}
+
+		routerRoutes = append(routerRoutes, result.RouterRoutes...)
+		tagDefs = append(tagDefs, result.TagDefs...)
+
+		for name, param := range result.Reusables.Params {
+			reusables.Params[name] = param
+		}
+
+		for name, response := range result.Reusables.Responses {
+			reusables.Responses[name] = response
+		}
+	}
+
+	return
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanSymbols(t *testing.T) {
+
+	lines := []string{
+		"// @tagdef users User management",
+		"// @paramdef RequestID X-Request-ID string in:header",
+		"// @route GetUsers GET /users",
+		"func GetUsers() {}",
+		"// @model User",
+		"type User struct {",
+		"}",
+	}
+
+	symbols := ScanSymbols(lines)
+
+	expected := map[string]int{"route": 2, "model": 4, "paramdef": 1, "tagdef": 0}
+
+	for tag, lineNum := range expected {
+		if len(symbols[tag]) != 1 || symbols[tag][0].LineNum != lineNum {
+			t.Errorf("ScanSymbols should have returned a %s symbol on line %d (actually %v)", tag, lineNum, symbols[tag])
+		}
+	}
+
+	if len(symbols["responsedef"]) != 0 {
+		t.Errorf("ScanSymbols should not have returned any responsedef symbols (actually %d)", len(symbols["responsedef"]))
+	}
+}
+
+func TestParseLines_Diagnostics(t *testing.T) {
+
+	lines := []string{
+		"// @route GetUsers GET /users",
+		"// @return abc User",
+		"func GetUsers() {}",
+	}
+
+	result := ParseLines(lines, "users.go", false)
+
+	if len(result.Routes["/users"]) != 1 {
+		t.Errorf("ParseLines should have returned 1 route (actually %d)", len(result.Routes["/users"]))
+	}
+
+	if len(result.Diagnostics) != 1 {
+		t.Errorf("ParseLines should have returned 1 diagnostic (actually %v)", result.Diagnostics)
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+
+	rootPath, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	filePaths := []string{}
+	for i := 0; i < 20; i++ {
+		filePath := filepath.Join(rootPath, fmt.Sprintf("file%02d.go", i))
+		source := fmt.Sprintf("// @route Get%d GET /things/%d\n// @return %d Thing\nfunc Get%d() {}\n\n// @model Thing\ntype Thing struct {\n\tID int `json:\"id\"` // Declared in file %d\n}\n", i, i, 200+i%2*800, i, i)
+		ioutil.WriteFile(filePath, []byte(source), 0644)
+		filePaths = append(filePaths, filePath)
+	}
+
+	sequential := ParseFiles(nil, filePaths, false, 1, nil)
+	concurrent := ParseFiles(nil, filePaths, false, 8, nil)
+
+	if !reflect.DeepEqual(sequential, concurrent) {
+		t.Errorf("ParseFiles should have returned the same results regardless of the number of workers")
+	}
+
+	for i, result := range concurrent {
+		if result.FilePath != filePaths[i] {
+			t.Errorf("ParseFiles should have returned %s at index %d (actually %s)", filePaths[i], i, result.FilePath)
+		}
+	}
+
+	_, models, _, _, _, _ := mergeFileResults(concurrent)
+
+	if models["Thing"].Fields[0].Description != "Declared in file 19" {
+		t.Errorf("mergeFileResults should have kept the last declaration of a model (actually '%s')", models["Thing"].Fields[0].Description)
+	}
+}
@@ -0,0 +1,174 @@
+/**
+ * PHP
+ */
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// phpFuncPattern finds the name of a function or method declaration
+var phpFuncPattern = regexp.MustCompile(`^\s*(?:(?:public|protected|private|static|final|abstract)\s+)*function\s+&?(\w+)`)
+
+// phpFieldPattern finds the modifiers, type, name and default value of a property declaration
+// Examples: `public string $email;`, `public ?int $age = null;`, `public $tags = [];`
+var phpFieldPattern = regexp.MustCompile(`^((?:(?:public|protected|private|static|readonly|var)\s+)+)(\??[\w\\|]+\s+)?\$(\w+)\s*(=.*)?;$`)
+
+// phpVarPattern finds the type of a `@var` docblock tag (e.g. `@var User[]`)
+var phpVarPattern = regexp.MustCompile(`^@var\s+(\S+)`)
+
+// phpTypes are the swagger types of php types
+var phpTypes = map[string]string{
+	"string":            SwaggerTypeString,
+	"int":               SwaggerTypeInt,
+	"integer":           SwaggerTypeInt,
+	"float":             SwaggerTypeFloat,
+	"double":            SwaggerTypeFloat,
+	"bool":              SwaggerTypeBool,
+	"boolean":           SwaggerTypeBool,
+	"DateTime":          SwaggerTypeString,
+	"DateTimeImmutable": SwaggerTypeString,
+	"DateTimeInterface": SwaggerTypeString,
+	"mixed":             "object",
+	"object":            "object",
+	"stdClass":          "object",
+	"array":             "object",
+	"iterable":          "object",
+	"Collection":        "object",
+	"ArrayObject":       "object",
+	"JsonSerializable":  "object",
+	"Carbon":            SwaggerTypeString,
+	"CarbonImmutable":   SwaggerTypeString,
+}
+
+// PHPLanguage reads annotations from php files, with models declared as classes with public properties
+// Example:
+//
+//	/**
+//	 * @model User
+//	 */
+//	class User
+//	{
+//	    /** The user's email */
+//	    public string $email;
+//	    public ?string $nickname = null;
+//	    /** @var string[] */
+//	    public array $tags = [];
+//	}
+type PHPLanguage struct{}
+
+// Name returns `php`
+func (PHPLanguage) Name() string {
+	return "php"
+}
+
+// Extensions returns the extension of php files
+func (PHPLanguage) Extensions() []string {
+	return []string{".php"}
+}
+
+// CommentSyntax returns the `//`, `#` and `/* */` comments of php
+func (PHPLanguage) CommentSyntax() CommentSyntax {
+	return CommentSyntax{LineComments: []string{"//", "#"}, BlockStart: "/*", BlockEnd: "*/"}
+}
+
+// FuncName returns the name of the function declared after the comment block, skipping attributes (e.g. `#[Route('/users')]`)
+func (PHPLanguage) FuncName(lines []string, blockStart int, blockEnd int) string {
+
+	for lineNum := blockEnd + 1; lineNum < len(lines); lineNum++ {
+
+		line := strings.TrimSpace(lines[lineNum])
+		if strings.HasPrefix(line, "#[") {
+			continue
+		}
+
+		if matches := phpFuncPattern.FindStringSubmatch(line); matches != nil {
+			return matches[1]
+		}
+		break
+	}
+
+	return ""
+}
+
+// ModelFields returns the public properties of the class declared after the comment block
+// Typed properties are required unless they are nullable (`?string`) or have a default value
+func (PHPLanguage) ModelFields(lines []string, commentLines []CommentLine, blockStart int, blockEnd int) []ModelField {
+	return classFields(lines, commentLines, blockEnd+1, func(line string, annotations []string, comments []string) (ModelField, bool) {
+		return ParsePHPField(line, comments)
+	})
+}
+
+// ParsePHPField parses a property declaration, along with its trailing comment and the `@var` tag of the comments above it
+// Returns false if the line is not a public instance property (e.g. a method, or a static property)
+func ParsePHPField(line string, comments []string) (field ModelField, ok bool) {
+
+	line, field.Description = splitTrailingComment(line, "//")
+
+	matches := phpFieldPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return
+	}
+
+	modifiers := strings.Fields(matches[1])
+	if inArray("static", modifiers) || inArray("protected", modifiers) || inArray("private", modifiers) {
+		return
+	}
+
+	field.Name = matches[3]
+
+	typeName := strings.TrimSpace(matches[2])
+	field.Required = len(typeName) > 0 && !strings.HasPrefix(typeName, "?") && len(matches[4]) == 0
+
+	if len(typeName) == 0 {
+		typeName = "mixed"
+	}
+
+	// The type of the items of arrays (and of untyped properties) is given by a `@var` tag
+	if typeName == "array" || typeName == "?array" || typeName == "mixed" {
+		for _, comment := range comments {
+			if matches := phpVarPattern.FindStringSubmatch(strings.TrimSpace(comment)); matches != nil {
+				typeName = matches[1]
+			}
+		}
+	}
+
+	setPHPFieldType(&field, strings.TrimPrefix(typeName, "?"))
+
+	ok = true
+	return
+}
+
+// setPHPFieldType sets the type of a field from a php type or a `@var` type (e.g. `User[]`, `array<int>`, `?string`)
+func setPHPFieldType(field *ModelField, typeName string) {
+
+	typeParts := []string{}
+	for _, typePart := range strings.Split(strings.TrimPrefix(typeName, "?"), "|") {
+		if typePart != "null" {
+			typeParts = append(typeParts, typePart)
+		}
+	}
+
+	if len(typeParts) != 1 {
+		setFieldType(field, "object", "object", false)
+		return
+	}
+
+	typeName = typeParts[0]
+	isArray := false
+
+	switch genericName, args := splitTypeArgs(typeName, "<", ">"); {
+	case strings.HasSuffix(typeName, "[]"):
+		typeName = strings.TrimSuffix(typeName, "[]")
+		isArray = true
+	case (genericName == "array" || genericName == "list") && len(args) > 0:
+		typeName = args[len(args)-1]
+		isArray = true
+	}
+
+	// Namespaced classes (e.g. `\App\Models\User`) are referenced by their name
+	typeName = typeName[strings.LastIndex(typeName, "\\")+1:]
+
+	setFieldType(field, phpTypes[typeName], typeName, isArray)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParsePHPField(t *testing.T) {
+
+	expected := map[string]ModelField{
+		"public string $email;":                   {Name: "email", Type: "string", Required: true},
+		"public ?int $age = null; // The age":     {Name: "age", Type: "integer", Description: "The age"},
+		"public bool $active = true;":             {Name: "active", Type: "boolean"},
+		`public \App\Models\Address $address;`:    {Name: "address", Type: "#object", Ref: "Address", Required: true},
+		"public readonly float $balance;":         {Name: "balance", Type: "number", Required: true},
+		"var $meta;":                              {Name: "meta", Type: "object"},
+		`public string $url = "http://acme.com";`: {Name: "url", Type: "string"},
+	}
+
+	for line, expectedField := range expected {
+		field, ok := ParsePHPField(line, nil)
+		if !ok || field.Name != expectedField.Name || field.Type != expectedField.Type || field.ItemsType != expectedField.ItemsType || field.Ref != expectedField.Ref || field.Required != expectedField.Required || field.Description != expectedField.Description {
+			t.Errorf("ParsePHPField should have returned %+v for '%s' (actually %+v)", expectedField, line, field)
+		}
+	}
+
+	field, _ := ParsePHPField("public array $tags = [];", []string{"The tags", "@var string[]"})
+	if field.Type != "array" || field.ItemsType != "string" {
+		t.Errorf("ParsePHPField should have returned an array of strings from the @var tag (actually %+v)", field)
+	}
+
+	for _, line := range []string{"private string $password;", "public static int $count = 0;", "public function getEmail(): string"} {
+		if _, ok := ParsePHPField(line, nil); ok {
+			t.Errorf("ParsePHPField should not have returned a field for '%s'", line)
+		}
+	}
+}
+
+func TestParseLines_PHP(t *testing.T) {
+
+	lines := []string{
+		"<?php",
+		"/**",
+		" * A user",
+		" * @model User",
+		" */",
+		"class User",
+		"{",
+		"    /**",
+		"     * The email",
+		"     * @format email",
+		"     */",
+		"    public string $email;",
+		"    /** @var Address[] */",
+		"    public array $addresses = [];",
+		"",
+		"    public function getEmail(): string",
+		"    {",
+		"        return $this->email;",
+		"    }",
+		"}",
+		"",
+		"class UserController",
+		"{",
+		"    /**",
+		"     * @route GetUser GET /users/{id}",
+		"     * @return 200 User",
+		"     */",
+		"    #[Route('/users/{id}', methods: ['GET'])]",
+		"    public function getUser(string $id): JsonResponse",
+		"    {",
+		"    }",
+		"}",
+	}
+
+	result := ParseLines(lines, "src/User.php", false)
+
+	model := result.Models["User"]
+	if model.Description != "A user" || len(model.Fields) != 2 {
+		t.Fatalf("ParseLines should have returned the model User with 2 fields (actually %+v)", model)
+	}
+
+	if model.Fields[0].Name != "email" || model.Fields[0].Description != "The email" || model.Fields[0].Tags[TagFormat][0] != "email" {
+		t.Errorf("ParseLines should have returned the field email with its comment (actually %+v)", model.Fields[0])
+	}
+
+	if model.Fields[1].Name != "addresses" || model.Fields[1].Type != "array" || model.Fields[1].Ref != "Address" || model.Fields[1].Description != "" {
+		t.Errorf("ParseLines should have returned the field addresses typed by its @var tag (actually %+v)", model.Fields[1])
+	}
+
+	if routes := result.Routes["/users/{id}"]; len(routes) != 1 || routes[0].Handler != "getUser" {
+		t.Errorf("ParseLines should have returned the route GetUser handled by getUser (actually %+v)", result.Routes)
+	}
+}
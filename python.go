@@ -0,0 +1,304 @@
+/**
+ * Python
+ */
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// pyFuncPattern finds the name of a function declaration
+var pyFuncPattern = regexp.MustCompile(`^\s*(?:async\s+)?def\s+(\w+)`)
+
+// pyClassPattern finds a class declaration
+var pyClassPattern = regexp.MustCompile(`^\s*class\s+\w+`)
+
+// pyFieldPattern finds the name and type of a class attribute
+var pyFieldPattern = regexp.MustCompile(`^(\w+)\s*:\s*(.+)$`)
+
+// pyAliasPattern finds the alias of a pydantic field (e.g. `Field(alias="firstName")`)
+var pyAliasPattern = regexp.MustCompile(`\balias\s*=\s*["']([^"']+)["']`)
+
+// pyTypes are the swagger types of python types
+var pyTypes = map[string]string{
+	"str":      SwaggerTypeString,
+	"int":      SwaggerTypeInt,
+	"float":    SwaggerTypeFloat,
+	"Decimal":  SwaggerTypeFloat,
+	"bool":     SwaggerTypeBool,
+	"bytes":    SwaggerTypeString,
+	"datetime": SwaggerTypeString,
+	"date":     SwaggerTypeString,
+	"time":     SwaggerTypeString,
+	"UUID":     SwaggerTypeString,
+	"EmailStr": SwaggerTypeString,
+	"HttpUrl":  SwaggerTypeString,
+	"AnyUrl":   SwaggerTypeString,
+	"Any":      "object",
+	"object":   "object",
+	"dict":     "object",
+	"Dict":     "object",
+	"Mapping":  "object",
+}
+
+// pyArrayTypes are the python types of lists
+var pyArrayTypes = []string{"list", "List", "Sequence", "set", "Set", "frozenset", "FrozenSet", "tuple", "Tuple", "Iterable"}
+
+// PythonLanguage reads annotations from python files, with models declared as dataclasses, pydantic models
+// or any class with annotated attributes. Annotations go in `#` comments or docstrings
+// Example:
+//
+//	# @model User
+//	class User(BaseModel):
+//	    # The user's email
+//	    # @format email
+//	    email: str
+//	    nickname: Optional[str] = None
+//	    tags: List[str] = Field(default_factory=list)
+type PythonLanguage struct{}
+
+// Name returns `python`
+func (PythonLanguage) Name() string {
+	return "python"
+}
+
+// Extensions returns the extension of python files
+func (PythonLanguage) Extensions() []string {
+	return []string{".py"}
+}
+
+// CommentSyntax returns the `#` comments and `"""` docstrings of python
+func (PythonLanguage) CommentSyntax() CommentSyntax {
+	return CommentSyntax{LineComments: []string{"#"}, BlockStart: `"""`, BlockEnd: `"""`}
+}
+
+// FuncName returns the name of the function declared after the comment block (skipping decorators),
+// or of the function the comment block is the docstring of
+func (PythonLanguage) FuncName(lines []string, blockStart int, blockEnd int) string {
+
+	for lineNum := blockEnd + 1; lineNum < len(lines); lineNum++ {
+		line := strings.TrimSpace(lines[lineNum])
+		if strings.HasPrefix(line, "@") {
+			continue
+		}
+		if matches := pyFuncPattern.FindStringSubmatch(line); matches != nil {
+			return matches[1]
+		}
+		break
+	}
+
+	// The signature of a function can span several lines
+	for lineNum := blockStart - 1; lineNum >= 0 && lineNum >= blockStart-maxRouterCallLines; lineNum-- {
+		if len(strings.TrimSpace(lines[lineNum])) == 0 {
+			break
+		}
+		if matches := pyFuncPattern.FindStringSubmatch(lines[lineNum]); matches != nil {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
+
+// ModelFields returns the annotated attributes of the class declared after the comment block (skipping decorators),
+// or of the class the comment block is the docstring of
+// Attributes are required unless they have a default value or an optional type (`Optional[str]`, `str | None`)
+func (PythonLanguage) ModelFields(lines []string, commentLines []CommentLine, blockStart int, blockEnd int) (fields []ModelField) {
+
+	classLine := -1
+
+	if blockStart > 0 && pyClassPattern.MatchString(lines[blockStart-1]) {
+		classLine = blockStart - 1
+	} else {
+		for lineNum := blockEnd + 1; lineNum < len(lines); lineNum++ {
+			line := strings.TrimSpace(lines[lineNum])
+			if strings.HasPrefix(line, "@") || len(line) == 0 {
+				continue
+			}
+			if pyClassPattern.MatchString(line) {
+				classLine = lineNum
+			}
+			break
+		}
+	}
+
+	if classLine < 0 {
+		return
+	}
+
+	classIndent := indentation(lines[classLine])
+	bodyIndent := -1
+	fieldComments := []string{}
+
+	for currentLine := classLine + 1; currentLine < len(lines); currentLine++ {
+
+		line := strings.TrimSpace(lines[currentLine])
+
+		if len(line) == 0 {
+			continue
+		}
+
+		// Docstrings (of the class, or of the attribute above) do not describe the next attribute
+		if commentLines[currentLine].IsComment && !strings.HasPrefix(line, "#") {
+			fieldComments = []string{}
+			continue
+		}
+
+		lineIndent := indentation(lines[currentLine])
+		if lineIndent <= classIndent {
+			break
+		}
+		if bodyIndent < 0 {
+			bodyIndent = lineIndent
+		}
+		if lineIndent > bodyIndent {
+			continue
+		}
+
+		if commentLine := commentLines[currentLine]; commentLine.IsComment {
+			if !commentLine.Skip {
+				fieldComments = append(fieldComments, strings.TrimSpace(commentLine.Text))
+			}
+			continue
+		}
+
+		if field, ok := ParsePythonField(line); ok {
+			fields = append(fields, describeField(field, fieldComments))
+		}
+
+		fieldComments = []string{}
+	}
+
+	return
+}
+
+// ParsePythonField parses an annotated class attribute, along with its default value and trailing comment
+// Returns false if the line is not an annotated attribute (e.g. a method, or a `ClassVar`)
+// Example: first_name: str = Field(..., alias="firstName")  # The user's first name
+func ParsePythonField(line string) (field ModelField, ok bool) {
+
+	line, field.Description = splitTrailingComment(line, "#")
+
+	matches := pyFieldPattern.FindStringSubmatch(line)
+	if matches == nil || strings.HasPrefix(matches[1], "_") {
+		return
+	}
+
+	field.Name = matches[1]
+	typeName, defaultValue := splitPythonDefault(matches[2])
+
+	if strings.HasPrefix(typeName, "ClassVar") {
+		return
+	}
+
+	swaggerType, typeName, isArray, isOptional := pythonType(typeName)
+	setFieldType(&field, swaggerType, typeName, isArray)
+
+	field.Required = !isOptional && !hasPythonDefault(defaultValue)
+
+	if aliasMatches := pyAliasPattern.FindStringSubmatch(defaultValue); aliasMatches != nil {
+		field.StructTag = reflect.StructTag(fmt.Sprintf(`json:"%s"`, aliasMatches[1]))
+	}
+
+	ok = true
+	return
+}
+
+// splitPythonDefault splits the type of an attribute from its default value,
+// ignoring the `=` of keyword arguments inside the type (e.g. `Annotated[int, Field(gt=0)]`)
+func splitPythonDefault(annotation string) (typeName string, defaultValue string) {
+
+	depth := 0
+
+	for i, char := range annotation {
+		switch {
+		case strings.ContainsRune("[(", char):
+			depth = depth + 1
+		case strings.ContainsRune("])", char):
+			depth = depth - 1
+		case char == '=' && depth == 0:
+			return strings.TrimSpace(annotation[0:i]), strings.TrimSpace(annotation[i+1:])
+		}
+	}
+
+	return strings.TrimSpace(annotation), ""
+}
+
+// hasPythonDefault checks if the default value of an attribute makes it optional
+// `Field(...)` and `field()` without a default keep the attribute required
+func hasPythonDefault(defaultValue string) bool {
+
+	if len(defaultValue) == 0 {
+		return false
+	}
+
+	name, args := splitTypeArgs(defaultValue, "(", ")")
+	if name != "Field" && name != "field" {
+		return true
+	}
+
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "default") || (i == 0 && len(arg) > 0 && !strings.Contains(arg, "=") && arg != "...") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pythonType returns the swagger type of a python type annotation (empty if it is a model),
+// along with the name of the type (or of the type of its items)
+func pythonType(annotation string) (swaggerType string, typeName string, isArray bool, isOptional bool) {
+
+	typeName = strings.Trim(strings.TrimSpace(annotation), `"'`)
+
+	genericName, args := splitTypeArgs(typeName, "[", "]")
+
+	switch {
+	case genericName == "Annotated" && len(args) > 0:
+		return pythonType(args[0])
+	case genericName == "Optional" && len(args) == 1:
+		swaggerType, typeName, isArray, _ = pythonType(args[0])
+		return swaggerType, typeName, isArray, true
+	case genericName == "Union" || (len(args) == 0 && strings.Contains(typeName, "|")):
+		if len(args) == 0 {
+			args = strings.Split(typeName, "|")
+		}
+		types := []string{}
+		for _, arg := range args {
+			if arg = strings.TrimSpace(arg); arg == "None" {
+				isOptional = true
+				continue
+			}
+			types = append(types, arg)
+		}
+		if len(types) != 1 {
+			return "object", "object", false, isOptional
+		}
+		swaggerType, typeName, isArray, _ = pythonType(types[0])
+		return
+	case genericName == "Literal":
+		return SwaggerTypeString, genericName, false, false
+	case inArray(genericName, pyArrayTypes):
+		if len(args) == 0 {
+			return "object", "object", true, false
+		}
+		swaggerType, typeName, _, _ = pythonType(args[0])
+		return swaggerType, typeName, true, false
+	}
+
+	typeName = genericName
+	if dotIdx := strings.LastIndex(typeName, "."); dotIdx > -1 {
+		typeName = typeName[dotIdx+1:]
+	}
+
+	return pyTypes[typeName], typeName, false, false
+}
+
+// indentation returns the number of spaces and tabs a line starts with
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParsePythonField(t *testing.T) {
+
+	expected := map[string]ModelField{
+		"email: str":                                    {Name: "email", Type: "string", Required: true},
+		"age: Optional[int] = None  # The age":          {Name: "age", Type: "integer", Description: "The age"},
+		"score: float | None":                           {Name: "score", Type: "number"},
+		"tags: List[str] = Field(default_factory=list)": {Name: "tags", Type: "array", ItemsType: "string"},
+		"friends: list['User']":                         {Name: "friends", Type: "array", Ref: "User", Required: true},
+		"owner: models.User = Field(...)":               {Name: "owner", Type: "#object", Ref: "User", Required: true},
+		"count: Annotated[int, Field(gt=0)] = 1":        {Name: "count", Type: "integer"},
+		"meta: Dict[str, Any] = {}":                     {Name: "meta", Type: "object"},
+	}
+
+	for line, expectedField := range expected {
+		field, ok := ParsePythonField(line)
+		if !ok || field.Name != expectedField.Name || field.Type != expectedField.Type || field.ItemsType != expectedField.ItemsType || field.Ref != expectedField.Ref || field.Required != expectedField.Required || field.Description != expectedField.Description {
+			t.Errorf("ParsePythonField should have returned %+v for '%s' (actually %+v)", expectedField, line, field)
+		}
+	}
+
+	field, _ := ParsePythonField(`first_name: str = Field(..., alias="firstName")`)
+	if field.JSONName() != "firstName" || !field.Required {
+		t.Errorf("ParsePythonField should have returned the required field firstName (actually %+v)", field)
+	}
+
+	for _, line := range []string{"_cache: dict = {}", "VERSION: ClassVar[int] = 1", "x = 1"} {
+		if _, ok := ParsePythonField(line); ok {
+			t.Errorf("ParsePythonField should not have returned a field for '%s'", line)
+		}
+	}
+}
+
+func TestParseLines_Python(t *testing.T) {
+
+	lines := []string{
+		"@dataclass",
+		"class Address:",
+		`    """`,
+		"    An address",
+		"",
+		"    @model Address",
+		`    """`,
+		"    city: str",
+		"",
+		"# @model User",
+		"@dataclass",
+		"class User:",
+		`    """Not a field description"""`,
+		"    # The email",
+		"    # @format email",
+		"    email: str",
+		"    address: Optional[Address] = None",
+		"",
+		"    def full_name(self) -> str:",
+		"        name: str = 'x'",
+		"        return name",
+		"",
+		"# Not a field of User",
+		"nickname: str = ''",
+		"",
+		`@router.get("/users/{id}")`,
+		"async def get_user(id: str):",
+		`    """`,
+		"    @route GetUser GET /users/{id}",
+		"    @return 200 User",
+		`    """`,
+	}
+
+	result := ParseLines(lines, "app/users.py", false)
+
+	if model := result.Models["Address"]; model.Description != "An address" || len(model.Fields) != 1 || model.Fields[0].Name != "city" {
+		t.Errorf("ParseLines should have returned the model Address from its docstring (actually %+v)", model)
+	}
+
+	model := result.Models["User"]
+	if len(model.Fields) != 2 {
+		t.Fatalf("ParseLines should have returned the model User with 2 fields (actually %+v)", model)
+	}
+
+	if model.Fields[0].Name != "email" || model.Fields[0].Description != "The email" || model.Fields[0].Tags[TagFormat][0] != "email" {
+		t.Errorf("ParseLines should have returned the field email with its comment (actually %+v)", model.Fields[0])
+	}
+
+	if model.Fields[1].Name != "address" || model.Fields[1].Ref != "Address" || model.Fields[1].Required {
+		t.Errorf("ParseLines should have returned the optional field address (actually %+v)", model.Fields[1])
+	}
+
+	if routes := result.Routes["/users/{id}"]; len(routes) != 1 || routes[0].Handler != "get_user" {
+		t.Errorf("ParseLines should have returned the route GetUser handled by get_user (actually %+v)", result.Routes)
+	}
+}
@@ -0,0 +1,122 @@
+/**
+ * Reusables
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// GetReusables searches `lines` for reusable parameter (@paramdef) and response (@responsedef) declarations
+// Examples:
+//
+//	@paramdef RequestID X-Request-ID string in:header optional Correlates the request with the logs
+//	@responsedef Unauthorized ErrorObj The request is not authenticated
+func GetReusables(lines []string, filePath string) (reusables Reusables) {
+	symbols := ScanSymbols(lines)
+	return getReusables(filePath, append(symbols[TagParamDef], symbols[TagResponseDef]...), log.Printf)
+}
+
+// getReusables parses the reusable parameters and responses of the @paramdef and @responsedef `symbols`,
+// reporting errors with `report`
+func getReusables(filePath string, symbols []Symbol, report reportFunc) (reusables Reusables) {
+
+	reusables.Params = map[string]Param{}
+	reusables.Responses = map[string]Response{}
+
+	for _, symbol := range symbols {
+
+		lineNum := symbol.LineNum
+		tagMap := ParseSymbols([]string{symbol.Line[strings.Index(symbol.Line, "@"):]})
+
+		for _, ret := range tagMap[TagParamDef] {
+			name, param, err := ParseParamDef(ret)
+			if err != nil {
+				report("Param Error: %s (File: %s; Line: %d)", err.Error(), filePath, lineNum)
+				continue
+			}
+			reusables.Params[name] = param
+		}
+
+		for _, ret := range tagMap[TagResponseDef] {
+			name, response, err := ParseResponseDef(ret)
+			if err != nil {
+				report("Response Error: %s (File: %s; Line: %d)", err.Error(), filePath, lineNum)
+				continue
+			}
+			reusables.Responses[name] = response
+		}
+	}
+
+	return
+}
+
+// ParseParamDef parses a reusable parameter declaration (@paramdef)
+// The name is followed by the same arguments as a @param tag
+// Example: @paramdef RequestID X-Request-ID string in:header optional Correlates the request with the logs
+func ParseParamDef(ret string) (name string, param Param, err error) {
+
+	retParts := strings.Fields(ret)
+
+	if len(retParts) < 3 {
+		err = fmt.Errorf("The tag @paramdef is not in the correct format: '%s'", ret)
+		return
+	}
+
+	name = strings.TrimPrefix(retParts[0], ReusablePrefix)
+	param, err = ParseRouteParam(strings.Join(retParts[1:], " "))
+
+	if err == nil && len(param.Ref) > 0 {
+		err = fmt.Errorf("@paramdef %s cannot reference another parameter", name)
+	}
+
+	return
+}
+
+// ParseResponseDef parses a reusable response declaration (@responsedef)
+// The name is followed by the same arguments as a @return tag, without the response code
+// Example: @responsedef Unauthorized ErrorObj The request is not authenticated
+func ParseResponseDef(ret string) (name string, response Response, err error) {
+
+	retParts := strings.Fields(ret)
+
+	if len(retParts) < 2 {
+		err = fmt.Errorf("The tag @responsedef is not in the correct format: '%s'", ret)
+		return
+	}
+
+	name = strings.TrimPrefix(retParts[0], ReusablePrefix)
+	response.SchemaRef = retParts[1]
+	response.Description = strings.Join(retParts[2:], " ")
+
+	if strings.HasPrefix(response.SchemaRef, ReusablePrefix) {
+		err = fmt.Errorf("@responsedef %s cannot reference another response", name)
+	}
+
+	return
+}
+
+// MarshalJSON outputs only the reference of parameters that reference a reusable parameter
+func (p Parameter) MarshalJSON() ([]byte, error) {
+
+	if len(p.Ref) > 0 {
+		return json.Marshal(map[string]string{"$ref": p.Ref})
+	}
+
+	type parameter Parameter
+	return json.Marshal(parameter(p))
+}
+
+// MarshalJSON outputs only the reference of responses that reference a reusable response
+func (r PathResponse) MarshalJSON() ([]byte, error) {
+
+	if len(r.Ref) > 0 {
+		return json.Marshal(map[string]string{"$ref": r.Ref})
+	}
+
+	type pathResponse PathResponse
+	return json.Marshal(pathResponse(r))
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestGetReusables(t *testing.T) {
+
+	lines := []string{
+		"// Package api",
+		"// @paramdef RequestID X-Request-ID string in:header optional Correlates the request with the logs",
+		"// @responsedef Unauthorized ErrorObj The request is not authenticated",
+		"package api",
+	}
+
+	reusables := GetReusables(lines, "some/file/path")
+
+	param, ok := reusables.Params["RequestID"]
+
+	if !ok {
+		t.Fatalf("GetReusables should have returned a param named RequestID")
+	}
+
+	if param.Name != "X-Request-ID" || param.In != "header" || param.Required {
+		t.Errorf("GetReusables should have returned an optional header param named X-Request-ID (actually %v)", param)
+	}
+
+	response, ok := reusables.Responses["Unauthorized"]
+
+	if !ok {
+		t.Fatalf("GetReusables should have returned a response named Unauthorized")
+	}
+
+	if response.SchemaRef != "ErrorObj" || response.Description != "The request is not authenticated" {
+		t.Errorf("GetReusables should have returned an ErrorObj response (actually %v)", response)
+	}
+}
+
+func TestParseParamDef_ShouldReturnError(t *testing.T) {
+
+	_, _, err := ParseParamDef("RequestID")
+
+	if err == nil {
+		t.Errorf("ParseParamDef should have returned an error (actually nil)")
+	}
+}
+
+func TestParameterMarshalJSON(t *testing.T) {
+
+	data, _ := Parameter{Ref: "#/parameters/RequestID", Required: true}.MarshalJSON()
+
+	if string(data) != `{"$ref":"#/parameters/RequestID"}` {
+		t.Errorf("Parameter.MarshalJSON should have only returned the reference (actually %s)", string(data))
+	}
+}
@@ -0,0 +1,378 @@
+/**
+ * Router
+ */
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// HTTPVerbs is a collection of the http verbs a route can be registered with
+var HTTPVerbs = []string{
+	"GET",
+	"POST",
+	"PUT",
+	"PATCH",
+	"DELETE",
+	"HEAD",
+	"OPTIONS",
+}
+
+// routerCallPattern finds router registration calls such as `r.HandleFunc(`, `r.Get(` or `e.GET(`
+var routerCallPattern = regexp.MustCompile(`\w+\.(HandleFunc|Handle|MethodFunc|Method|Get|Post|Put|Patch|Delete|Head|Options|GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\(`)
+
+// routerParamPattern finds echo/gin style path params (e.g. `:id`)
+var routerParamPattern = regexp.MustCompile(`:(\w+)`)
+
+// funcNamePattern finds the name of a function or method declaration
+var funcNamePattern = regexp.MustCompile(`^\s*func\s+(?:\([^)]*\)\s*)?(\w+)`)
+
+// maxRouterCallLines is the maximum number of lines a single registration call can span
+const maxRouterCallLines = 10
+
+// isHTTPVerb checks if `verb` is a known http verb
+func isHTTPVerb(verb string) bool {
+	return inArray(strings.ToUpper(verb), HTTPVerbs)
+}
+
+// GetFuncName returns the name of the function declared on `line`, or an empty string if there is none
+func GetFuncName(line string) string {
+	matches := funcNamePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// GetRouterRoutes searches `lines` for route registration calls to gorilla/mux, chi, echo and gin routers
+// Examples:
+//
+//	r.HandleFunc("/users/{id}", h.GetUser).Methods("GET")
+//	r.Get("/users/{id}", h.GetUser)
+//	e.GET("/users/:id", h.GetUser)
+func GetRouterRoutes(lines []string, filePath string) (routerRoutes []RouterRoute) {
+
+	for lineNum := 0; lineNum < len(lines); lineNum++ {
+
+		if strings.HasPrefix(strings.TrimSpace(lines[lineNum]), "//") {
+			continue
+		}
+
+		locs := routerCallPattern.FindAllStringSubmatchIndex(lines[lineNum], -1)
+		if len(locs) == 0 {
+			continue
+		}
+
+		// Registration calls can be split across several lines
+		call := lines[lineNum]
+		for i := lineNum + 1; i < len(lines) && i < lineNum+maxRouterCallLines && parenDepth(call) > 0; i++ {
+			call = call + " " + strings.TrimSpace(lines[i])
+		}
+
+		for _, loc := range locs {
+			method := call[loc[2]:loc[3]]
+			args, end := splitCallArgs(call, loc[1])
+			routerRoutes = append(routerRoutes, parseRouterCall(method, args, call[end:], filePath, lineNum)...)
+		}
+	}
+
+	return
+}
+
+// parseRouterCall builds router routes from the name and arguments of a registration call
+// `rest` is whatever follows the call, which is where gorilla/mux chains `.Methods(...)`
+func parseRouterCall(method string, args []string, rest string, filePath string, lineNum int) (routerRoutes []RouterRoute) {
+
+	verbs := []string{}
+	pathIdx := 0
+
+	switch method {
+	case "HandleFunc", "Handle":
+		// gin: router.Handle("GET", "/users", h.GetUsers)
+		if len(args) > 2 && isHTTPVerb(unquote(args[0])) {
+			verbs = append(verbs, strings.ToUpper(unquote(args[0])))
+			pathIdx = 1
+			break
+		}
+
+		// gorilla/mux: r.HandleFunc("/users", h.GetUsers).Methods("GET", "HEAD")
+		if strings.HasPrefix(rest, ".Methods(") {
+			methodArgs, _ := splitCallArgs(rest, len(".Methods("))
+			for _, methodArg := range methodArgs {
+				verb := strings.ToUpper(strings.TrimPrefix(unquote(methodArg), "http.Method"))
+				if isHTTPVerb(verb) {
+					verbs = append(verbs, verb)
+				}
+			}
+		}
+	case "Method", "MethodFunc":
+		// chi: r.Method("GET", "/users", h.GetUsers)
+		if len(args) < 3 {
+			return
+		}
+		verbs = append(verbs, strings.ToUpper(strings.TrimPrefix(unquote(args[0]), "http.Method")))
+		pathIdx = 1
+	default:
+		// chi: r.Get("/users", h.GetUsers), echo/gin: e.GET("/users", h.GetUsers)
+		verbs = append(verbs, strings.ToUpper(method))
+	}
+
+	if len(args) < pathIdx+2 {
+		return
+	}
+
+	path := unquote(args[pathIdx])
+
+	// Only string literal paths can be documented
+	if !strings.HasPrefix(path, "/") || path == args[pathIdx] {
+		return
+	}
+
+	handlers := []string{}
+	for _, arg := range args[pathIdx+1:] {
+		if handler := handlerName(arg); len(handler) > 0 {
+			handlers = append(handlers, handler)
+		}
+	}
+
+	if len(handlers) == 0 {
+		return
+	}
+
+	// Without a verb (e.g. gorilla/mux without `.Methods()`) the route matches every verb
+	if len(verbs) == 0 {
+		verbs = append(verbs, "")
+	}
+
+	for _, verb := range verbs {
+		routerRoutes = append(routerRoutes, RouterRoute{
+			FilePath: filePath,
+			LineNum:  lineNum,
+			Verb:     verb,
+			Path:     NormalizeRouterPath(path),
+			Handlers: handlers,
+		})
+	}
+
+	return
+}
+
+// NormalizeRouterPath converts router specific path params (`:id`, `{id:[0-9]+}`) to swagger path params (`{id}`)
+// The pattern of a gorilla/mux param ends at its matching brace, as it can contain braces itself (e.g. `{code:[a-z]{3}}`)
+func NormalizeRouterPath(path string) string {
+
+	normalized := ""
+
+	for i := 0; i < len(path); i++ {
+
+		end := matchingBrace(path, i)
+		if end < 0 {
+			normalized = normalized + path[i:i+1]
+			continue
+		}
+
+		name := path[i+1 : end]
+		if colonIdx := strings.Index(name, ":"); colonIdx > -1 {
+			name = name[0:colonIdx]
+		}
+
+		normalized = normalized + "{" + name + "}"
+		i = end
+	}
+
+	return routerParamPattern.ReplaceAllString(normalized, "{$1}")
+}
+
+// matchingBrace returns the position of the brace closing the one at `start` in `s`,
+// or -1 if there is no brace at `start` or it is never closed
+func matchingBrace(s string, start int) int {
+
+	if s[start] != '{' {
+		return -1
+	}
+
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// handlerName returns the name of the function referenced by a handler argument
+// Examples: `h.GetUser` => `GetUser`, `http.HandlerFunc(h.GetUser)` => `GetUser`
+func handlerName(arg string) string {
+
+	arg = strings.TrimSpace(arg)
+
+	// Anonymous functions have no name to match against
+	if strings.HasPrefix(arg, "func") {
+		return ""
+	}
+
+	// Unwrap adapters and middleware (e.g. `http.HandlerFunc(h.GetUser)`)
+	if strings.HasSuffix(arg, ")") {
+		if openIdx := strings.Index(arg, "("); openIdx > -1 {
+			innerArgs, _ := splitCallArgs(arg, openIdx+1)
+			if len(innerArgs) == 0 {
+				return ""
+			}
+			return handlerName(innerArgs[len(innerArgs)-1])
+		}
+	}
+
+	if strings.HasPrefix(arg, "\"") || strings.HasPrefix(arg, "`") {
+		return ""
+	}
+
+	parts := strings.Split(arg, ".")
+	return strings.TrimPrefix(parts[len(parts)-1], "&")
+}
+
+// splitCallArgs splits the arguments of a function call starting at `start` (the position just after the opening paren)
+// It returns the trimmed arguments and the position just after the closing paren
+func splitCallArgs(s string, start int) (args []string, end int) {
+
+	depth := 0
+	argStart := start
+	var quote byte
+
+	for end = start; end < len(s); end++ {
+		c := s[end]
+
+		if quote != 0 {
+			if c == '\\' && quote == '"' {
+				end++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '`', '\'':
+			quote = c
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			if depth == 0 {
+				if arg := strings.TrimSpace(s[argStart:end]); len(arg) > 0 {
+					args = append(args, arg)
+				}
+				end++
+				return
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[argStart:end]))
+				argStart = end + 1
+			}
+		}
+	}
+
+	if arg := strings.TrimSpace(s[argStart:]); len(arg) > 0 {
+		args = append(args, arg)
+	}
+
+	return
+}
+
+// parenDepth returns the number of unclosed parens in `s`
+func parenDepth(s string) (depth int) {
+	for _, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+	}
+	return
+}
+
+// unquote removes the surrounding quotes from a string literal
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '`') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// ResolveRouterRoutes fills in the verb and path of routes whose @route tag omits them from the matching router
+// registrations, and logs any disagreement between a @route tag and the router
+func ResolveRouterRoutes(routes []Route, routerRoutes []RouterRoute) (resolved []Route) {
+
+	byHandler := map[string][]RouterRoute{}
+	for _, routerRoute := range routerRoutes {
+		for _, handler := range routerRoute.Handlers {
+			byHandler[handler] = append(byHandler[handler], routerRoute)
+		}
+	}
+
+	for _, route := range routes {
+
+		handler := route.Handler
+		if len(handler) == 0 {
+			handler = route.OperationID
+		}
+
+		candidates := []RouterRoute{}
+		for _, routerRoute := range byHandler[handler] {
+			if len(route.Verb) > 0 && len(routerRoute.Verb) > 0 && routerRoute.Verb != route.Verb {
+				continue
+			}
+			if len(route.Path) > 0 && routerRoute.Path != route.Path {
+				continue
+			}
+			candidates = append(candidates, routerRoute)
+		}
+
+		if len(candidates) == 0 {
+			if len(byHandler[handler]) > 0 {
+				routerRoute := byHandler[handler][0]
+				log.Printf("Route Warning: @route %s declares %s but the router registers %s (%s:%d)", route.OperationID, describeRoute(route.Verb, route.Path), describeRoute(routerRoute.Verb, routerRoute.Path), routerRoute.FilePath, routerRoute.LineNum+1)
+			}
+			resolved = append(resolved, route)
+			continue
+		}
+
+		if len(route.Verb) == 0 || len(route.Path) == 0 {
+			if len(candidates) > 1 {
+				log.Printf("Route Warning: handler %s for @route %s is registered %d times; using %s", handler, route.OperationID, len(candidates), describeRoute(candidates[0].Verb, candidates[0].Path))
+			}
+			if len(route.Verb) == 0 {
+				route.Verb = candidates[0].Verb
+			}
+			if len(route.Path) == 0 {
+				route.Path = candidates[0].Path
+			}
+		}
+
+		resolved = append(resolved, route)
+	}
+
+	return
+}
+
+// describeRoute formats a verb and path for log messages
+func describeRoute(verb string, path string) string {
+	if len(verb) == 0 {
+		verb = "*"
+	}
+	if len(path) == 0 {
+		path = "(no path)"
+	}
+	return fmt.Sprintf("%s %s", verb, path)
+}
@@ -0,0 +1,111 @@
+package main
+
+import "testing"
+
+func TestGetRouterRoutes(t *testing.T) {
+
+	lines := []string{
+		"func routes(r *mux.Router, h *Handler) {",
+		"	r.HandleFunc(\"/users/{id:[0-9]+}\", h.GetUser).Methods(\"GET\")",
+		"	r.Get(\"/users\", h.GetUsers)",
+		"	e.POST(\"/users/:id/tags\", h.CreateUserTag)",
+		"	r.Handle(\"/health\", http.HandlerFunc(h.Health))",
+		"	// r.Get(\"/commented\", h.Commented)",
+		"	r.HandleFunc(\"/users/{id}\",",
+		"		h.UpdateUser,",
+		"	).Methods(http.MethodPut, http.MethodPatch)",
+		"	q.Get(\"status\")",
+		"	r.HandleFunc(\"/countries/{code:[a-z]{2,3}}/cities/{id:[0-9]+}\", h.GetCity).Methods(\"GET\")",
+		"}",
+	}
+
+	routerRoutes := GetRouterRoutes(lines, "some/file/path")
+
+	expected := []RouterRoute{
+		{Verb: "GET", Path: "/users/{id}", Handlers: []string{"GetUser"}},
+		{Verb: "GET", Path: "/users", Handlers: []string{"GetUsers"}},
+		{Verb: "POST", Path: "/users/{id}/tags", Handlers: []string{"CreateUserTag"}},
+		{Verb: "", Path: "/health", Handlers: []string{"Health"}},
+		{Verb: "PUT", Path: "/users/{id}", Handlers: []string{"UpdateUser"}},
+		{Verb: "PATCH", Path: "/users/{id}", Handlers: []string{"UpdateUser"}},
+		{Verb: "GET", Path: "/countries/{code}/cities/{id}", Handlers: []string{"GetCity"}},
+	}
+
+	if len(routerRoutes) != len(expected) {
+		t.Fatalf("GetRouterRoutes should have returned %d router routes (actually %d)", len(expected), len(routerRoutes))
+	}
+
+	for i, e := range expected {
+		actual := routerRoutes[i]
+		if actual.Verb != e.Verb || actual.Path != e.Path || len(actual.Handlers) != 1 || actual.Handlers[0] != e.Handlers[0] {
+			t.Errorf("GetRouterRoutes should have returned %s %s %v at index %d (actually %s %s %v)", e.Verb, e.Path, e.Handlers, i, actual.Verb, actual.Path, actual.Handlers)
+		}
+	}
+}
+
+func TestGetFuncName(t *testing.T) {
+
+	if name := GetFuncName("func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {"); name != "GetUser" {
+		t.Errorf("GetFuncName should have returned '%s' (actually '%s')", "GetUser", name)
+	}
+
+	if name := GetFuncName("func GetUsers(c echo.Context) error {"); name != "GetUsers" {
+		t.Errorf("GetFuncName should have returned '%s' (actually '%s')", "GetUsers", name)
+	}
+
+	if name := GetFuncName("type Foo struct {"); name != "" {
+		t.Errorf("GetFuncName should have returned an empty string (actually '%s')", name)
+	}
+}
+
+func TestResolveRouterRoutes(t *testing.T) {
+
+	routes := []Route{
+		{OperationID: "GetUser", Handler: "GetUser"},
+		{OperationID: "UpdateUser", Handler: "UpdateUser", Verb: "PATCH"},
+		{OperationID: "DeleteUser", Verb: "DELETE", Path: "/users/{id}"},
+	}
+
+	routerRoutes := []RouterRoute{
+		{Verb: "GET", Path: "/users/{id}", Handlers: []string{"GetUser"}},
+		{Verb: "PUT", Path: "/users/{id}", Handlers: []string{"UpdateUser"}},
+		{Verb: "PATCH", Path: "/users/{userId}", Handlers: []string{"UpdateUser"}},
+		{Verb: "POST", Path: "/users/{id}/delete", Handlers: []string{"DeleteUser"}},
+	}
+
+	resolved := ResolveRouterRoutes(routes, routerRoutes)
+
+	if len(resolved) != 3 {
+		t.Fatalf("ResolveRouterRoutes should have returned %d routes (actually %d)", 3, len(resolved))
+	}
+
+	if resolved[0].Verb != "GET" || resolved[0].Path != "/users/{id}" {
+		t.Errorf("ResolveRouterRoutes should have resolved GetUser to GET /users/{id} (actually %s %s)", resolved[0].Verb, resolved[0].Path)
+	}
+
+	if resolved[1].Verb != "PATCH" || resolved[1].Path != "/users/{userId}" {
+		t.Errorf("ResolveRouterRoutes should have resolved UpdateUser to PATCH /users/{userId} (actually %s %s)", resolved[1].Verb, resolved[1].Path)
+	}
+
+	// Disagreements are reported but the annotation wins
+	if resolved[2].Verb != "DELETE" || resolved[2].Path != "/users/{id}" {
+		t.Errorf("ResolveRouterRoutes should have kept DeleteUser as DELETE /users/{id} (actually %s %s)", resolved[2].Verb, resolved[2].Path)
+	}
+}
+
+func TestNormalizeRouterPath(t *testing.T) {
+
+	tests := map[string]string{
+		"/users/:id/tags/:tag":       "/users/{id}/tags/{tag}",
+		"/users/{id:[0-9]+}":         "/users/{id}",
+		"/countries/{code:[a-z]{3}}": "/countries/{code}",
+		"/files/{path:.*}/{name}":    "/files/{path}/{name}",
+		"/broken/{id:[0-9]{2}/users": "/broken/{id:[0-9]{2}/users",
+	}
+
+	for path, expected := range tests {
+		if actual := NormalizeRouterPath(path); actual != expected {
+			t.Errorf("NormalizeRouterPath should have returned %s for %s (actually %s)", expected, path, actual)
+		}
+	}
+}
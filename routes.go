@@ -9,13 +9,12 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // GetRoutes returns a map of route arrays indexed by their path
 func GetRoutes(lines []string, filePath string) (routes map[string][]Route, err error) {
 
-	routes = map[string][]Route{}
-
 	var symbols []Symbol
 
 	// Find where the symbols live
@@ -25,15 +24,21 @@ func GetRoutes(lines []string, filePath string) (routes map[string][]Route, err
 		return
 	}
 
-	// If no symbols are found, skip
-	if len(symbols) == 0 {
-		return
-	}
+	routes = getRoutes(GoLanguage{}, lines, ExtractComments(lines), filePath, symbols, log.Printf)
+
+	return
+}
+
+// getRoutes parses the routes of the @route `symbols` found in `lines` (whose comments are `commentLines`)
+// of a file written in `language`, reporting errors with `report`
+func getRoutes(language Language, lines []string, commentLines []CommentLine, filePath string, symbols []Symbol, report reportFunc) (routes map[string][]Route) {
+
+	routes = map[string][]Route{}
 
 	for _, symbol := range symbols {
 
 		route := Route{}
-		comments, _, blockEnd := GetCommentBlock(lines, symbol.LineNum)
+		comments, blockStart, blockEnd := getCommentBlock(commentLines, symbol.LineNum)
 
 		// Parse the symbols inside this comment block
 		symbolMap := ParseSymbols(comments)
@@ -41,21 +46,24 @@ func GetRoutes(lines []string, filePath string) (routes map[string][]Route, err
 		// Check that the route field exists (only use the first)
 		// Note: There may be more than one route tag, but anything after the first is ignored
 		if _, ok := symbolMap[TagRoute]; !ok {
-			log.Printf("No routes found at path %s", filePath)
+			report("No routes found at path %s", filePath)
 			continue
 		}
 
 		if len(symbolMap[TagRoute]) < 1 {
-			log.Printf("No routes found at path %s", filePath)
+			report("No routes found at path %s", filePath)
 			continue
 		}
 
 		route, routeErr := ParseRoute(symbolMap[TagRoute][0], blockEnd+1, filePath, comments)
 
 		if routeErr != nil {
-			log.Printf("Route Error: %s", routeErr.Error())
+			report("Route Error: %s", routeErr.Error())
+			continue
 		}
 
+		route.Handler = language.FuncName(lines, blockStart, blockEnd)
+
 		// Return tags
 		if _, ok := symbolMap[TagReturn]; ok {
 			for _, ret := range symbolMap[TagReturn] {
@@ -63,6 +71,7 @@ func GetRoutes(lines []string, filePath string) (routes map[string][]Route, err
 				response, err := ParseRouteResponse(ret)
 
 				if err != nil {
+					report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
 					continue
 				}
 
@@ -70,33 +79,129 @@ func GetRoutes(lines []string, filePath string) (routes map[string][]Route, err
 			}
 		}
 
+		// Example tags
+		if _, ok := symbolMap[TagExample]; ok {
+			for _, ret := range symbolMap[TagExample] {
+				example, err := ParseRouteExample(ret)
+				if err != nil {
+					report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
+					continue
+				}
+				route.Examples = append(route.Examples, example)
+			}
+		}
+
+		// Header tags
+		if _, ok := symbolMap[TagHeader]; ok {
+			for _, ret := range symbolMap[TagHeader] {
+				header, err := ParseRouteHeader(ret)
+				if err != nil {
+					report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
+					continue
+				}
+				route.Headers = append(route.Headers, header)
+			}
+		}
+
 		// Param tags
 		if _, ok := symbolMap[TagParam]; ok {
 			for _, ret := range symbolMap[TagParam] {
 				param, err := ParseRouteParam(ret)
 				if err != nil {
+					report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
 					continue
 				}
 				route.Params = append(route.Params, param)
 			}
 		}
 
-		if _, ok := symbolMap[TagTags]; ok {
-			for _, ret := range symbolMap[TagTags] {
+		// Query struct tags (expanded into a param per field)
+		if _, ok := symbolMap[TagQueryParams]; ok {
+			for _, ret := range symbolMap[TagQueryParams] {
+				for _, modelName := range strings.Fields(ret) {
+					route.Params = append(route.Params, Param{
+						Name:     modelName,
+						Type:     modelName,
+						In:       TransportQuery,
+						Required: true,
+					})
+				}
+			}
+		}
+
+		// @tag and @tags are interchangeable
+		if routeTags := append(append([]string{}, symbolMap[TagTag]...), symbolMap[TagTags]...); len(routeTags) > 0 {
+			for _, ret := range routeTags {
 				tags, err := ParseRouteTag(ret)
 				if err != nil {
 					continue
 				}
 
-				route.Tags = tags
+				// A tag is only added once, in the order it was first seen
+				for _, tag := range tags {
+					if !inArray(tag, route.Tags) {
+						route.Tags = append(route.Tags, tag)
+					}
+				}
+			}
+		}
+
+		if _, ok := symbolMap[TagConsumes]; ok {
+			for _, ret := range symbolMap[TagConsumes] {
+				mimeTypes, err := ParseRouteMimeTypes(ret)
+				if err != nil {
+					report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
+					continue
+				}
+				route.Consumes = append(route.Consumes, mimeTypes...)
+			}
+		}
+
+		if _, ok := symbolMap[TagProduces]; ok {
+			for _, ret := range symbolMap[TagProduces] {
+				mimeTypes, err := ParseRouteMimeTypes(ret)
+				if err != nil {
+					report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
+					continue
+				}
+				route.Produces = append(route.Produces, mimeTypes...)
+			}
+		}
+
+		// The security of the route is only set (and an empty list disables it) once a requirement or `none` is parsed,
+		// so invalid @security tags do not turn off the global security
+		if _, ok := symbolMap[TagSecurity]; ok {
+			for _, ret := range symbolMap[TagSecurity] {
+				requirement, err := ParseRouteSecurity(ret)
+				if err != nil {
+					report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
+					continue
+				}
+
+				// @security none
+				if requirement == nil {
+					if route.Security == nil {
+						route.Security = []SecurityRequirement{}
+					}
+					continue
+				}
+
+				route.Security = append(route.Security, requirement)
 			}
 		}
 
-		if filePath == "../cherry/api/routes/tasks.go" && route.Path == "/tasks/{task_id}" && route.Verb == "PUT" {
-			fmt.Printf("%s - %s %s\n", filePath, route.Verb, route.Path)
-			fmt.Printf("%v\n", symbolMap)
-			fmt.Printf("%v\n", route.Tags)
+		deprecation, err := ParseDeprecation(symbolMap)
+		if err != nil {
+			report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
+		}
+		route.Deprecation = deprecation
+
+		extensions, err := ParseExtensions(symbolMap)
+		if err != nil {
+			report("Route Error: %s (File: %s; Line: %d)", err.Error(), filePath, route.LineNum)
 		}
+		route.Extensions = extensions
+
 		if _, ok := routes[route.Path]; !ok {
 			routes[route.Path] = []Route{}
 		}
@@ -108,6 +213,8 @@ func GetRoutes(lines []string, filePath string) (routes map[string][]Route, err
 }
 
 // ParseRoute parses a route from a line
+// The verb and path are optional so they can be discovered from router registrations
+// Examples: `GetFoo GET /foo Returns a foo`, `GetFoo Returns a foo`
 func ParseRoute(line string, lineNum int, filePath string, comments []string) (route Route, err error) {
 
 	// Assume that after the block ends, so the method starts
@@ -115,105 +222,317 @@ func ParseRoute(line string, lineNum int, filePath string, comments []string) (r
 	route.FilePath = filePath
 	routeParts := strings.Fields(line)
 
-	if len(routeParts) < 2 {
+	if len(routeParts) < 1 {
 		err = fmt.Errorf("The tag @route is not in the correct format. File: %s; Line: %d", filePath, route.LineNum)
 		return
 	}
 
 	route.OperationID = routeParts[0]
-	route.Verb = routeParts[1]
-	route.Path = routeParts[2]
+	partIdx := 1
+
+	if len(routeParts) > partIdx && isHTTPVerb(routeParts[partIdx]) {
+		route.Verb = strings.ToUpper(routeParts[partIdx])
+		partIdx = partIdx + 1
+	}
+
+	if len(routeParts) > partIdx && strings.HasPrefix(routeParts[partIdx], "/") {
+		route.Path = routeParts[partIdx]
+		partIdx = partIdx + 1
+	}
+
+	// The text after the path is the summary, while the free text of the comment block
+	// (or a @description tag) is the markdown description
+	routeText := strings.Join(routeParts[partIdx:], " ")
+	tags, description := parseCommentBlock(comments)
 
-	if len(comments) > 0 {
-		route.Description = comments[0]
+	if descriptions, ok := tags[TagDescription]; ok {
+		description = strings.Join(descriptions, "\n\n")
+	}
+
+	route.Description = description
+	route.Summary = routeText
+
+	if len(route.Description) == 0 {
+		route.Description = routeText
+	}
+
+	// Descriptions loaded from a file are summarized once they are loaded
+	if len(route.Summary) == 0 && !IsDescriptionFile(route.Description) {
+		route.Summary = Summarize(route.Description)
 	}
 
 	return
 }
 
+// ParseRouteParam parses a route's parameter tag (@param)
+// Options (e.g. `in:query`, `optional`, `default:20`) follow the type and the remainder is the description
+// Example: @param limit int in:query optional default:20 min:1 max:100 The number of results
 func ParseRouteParam(ret string) (param Param, err error) {
 
 	retParts := strings.Fields(ret)
 
 	retPartLen := len(retParts)
 
-	if retPartLen < 2 {
-		// invalid
+	// Reference to a reusable parameter (e.g. `@param $RequestID`)
+	if retPartLen > 0 && strings.HasPrefix(retParts[0], ReusablePrefix) {
+		param.Ref = retParts[0][len(ReusablePrefix):]
+		return
+	}
 
+	if retPartLen < 2 {
+		err = fmt.Errorf("The tag @param is not in the correct format: '%s'", ret)
+		return
 	}
 
 	param.Name = retParts[0]
-	switch {
-	case retParts[1][0:3] == GoTypeInt:
-		param.Type = SwaggerTypeInt
-	case retParts[1] == GoTypeBool:
-		param.Type = SwaggerTypeBool
-	case retParts[1] == GoTypeString:
-		param.Type = SwaggerTypeString
-	case len(retParts[1]) >= 5 && retParts[1][0:5] == GoTypeFloat:
-		param.Type = SwaggerTypeFloat
-	default:
-		param.Type = retParts[1]
+	if strings.HasPrefix(retParts[1], "[]") {
+		param.Type = SwaggerTypeArray
+		param.ItemsType = swaggerType(retParts[1][2:])
+	} else {
+		param.Type = swaggerType(retParts[1])
 	}
 	param.Required = true
 
 	curIdx := 2
-	maxIdx := 4
-	for {
-		if retPartLen <= curIdx {
+	for ; curIdx < retPartLen; curIdx++ {
+		var isOption bool
+		if isOption, err = parseParamOption(&param, retParts[curIdx]); err != nil {
+			return
+		}
+		if !isOption {
 			break
 		}
-		if len(retParts[curIdx]) > 3 && retParts[curIdx][0:len(TagArgTransportPrefix)] == TagArgTransportPrefix {
+	}
 
-			ins := []string{
-				TransportPath,
-				TransportQuery,
-				TransportForm,
-				TransportHeader,
-				TransportBody,
-			}
+	// add the description
+	param.Description = strings.Join(retParts[curIdx:], " ")
 
-			if !inArray(retParts[curIdx][3:], ins) {
-				err = fmt.Errorf("Invalid transport '%s'", retParts[curIdx][len(TagArgTransportPrefix):])
-				return
-			}
+	// Swagger 2.0 only uploads a single file per param
+	if param.ItemsType == SwaggerTypeFile {
+		err = fmt.Errorf("file param '%s' cannot be an array", param.Name)
+		return
+	}
 
-			param.In = retParts[curIdx][3:]
+	// File uploads can only be sent as form data
+	if param.Type == SwaggerTypeFile {
+		if len(param.In) == 0 {
+			param.In = TransportFormData
+		}
+		if param.In != TransportFormData {
+			err = fmt.Errorf("file param '%s' must be in:form", param.Name)
+			return
 		}
+	}
 
-		if len(retParts[curIdx]) == 8 && (retParts[curIdx] == TagArgOptional || retParts[curIdx] == TagArgRequired) {
-			param.Required = retParts[curIdx] == TagArgRequired
+	if len(param.In) == 0 {
+		param.In = TransportQuery
+	}
+
+	if len(param.CollectionFormat) > 0 && param.Type != SwaggerTypeArray {
+		err = fmt.Errorf("collectionFormat can only be used with array params ('%s')", param.Name)
+		return
+	}
+
+	valueType := param.Type
+	if valueType == SwaggerTypeArray {
+		valueType = param.ItemsType
+	}
+
+	for _, enumValue := range param.Enum {
+		if _, err = typedValue(valueType, enumValue); err != nil {
+			return
 		}
+	}
 
-		curIdx = curIdx + 1
-		if maxIdx <= curIdx {
-			break
+	if len(param.Default) > 0 {
+		_, err = typedDefault(param)
+	}
+
+	return
+}
+
+// parseParamOption applies a single @param option (e.g. `in:query`, `optional`, `enum:a|b|c`) to `param`
+// Returns false if `option` is not an option, which is where the description starts
+func parseParamOption(param *Param, option string) (isOption bool, err error) {
+
+	if option == TagArgRequired || option == TagArgOptional {
+		param.Required = option == TagArgRequired
+		return true, nil
+	}
+
+	colonIdx := strings.Index(option, ":")
+	if colonIdx < 1 {
+		return false, nil
+	}
+
+	name := option[0 : colonIdx+1]
+	value := option[colonIdx+1:]
+
+	switch name {
+	case TagArgTransportPrefix:
+		ins := []string{
+			TransportPath,
+			TransportQuery,
+			TransportForm,
+			TransportFormData,
+			TransportHeader,
+			TransportBody,
+		}
+
+		if !inArray(value, ins) {
+			err = fmt.Errorf("Invalid transport '%s'", value)
+			return
+		}
+
+		// Swagger calls form params `formData`
+		if value == TransportForm {
+			value = TransportFormData
 		}
+
+		param.In = value
+	case TagArgCollectionFormatPrefix:
+		if !inArray(value, CollectionFormats) {
+			err = fmt.Errorf("Invalid collectionFormat '%s'", value)
+			return
+		}
+		param.CollectionFormat = value
+	case TagArgEnumPrefix:
+		param.Enum = strings.Split(value, "|")
+	case TagArgDefaultPrefix:
+		param.Default = value
+	case TagArgMinPrefix, TagArgMaxPrefix:
+		var bound float64
+		if bound, err = strconv.ParseFloat(value, 64); err != nil {
+			err = fmt.Errorf("Invalid %s value '%s'", name[0:len(name)-1], value)
+			return
+		}
+		if name == TagArgMinPrefix {
+			param.Minimum = &bound
+		} else {
+			param.Maximum = &bound
+		}
+	case TagArgPatternPrefix:
+		param.Pattern = value
+	case TagArgFormatPrefix:
+		param.Format = value
+	default:
+		return false, nil
 	}
 
-	// add the description
-	if retPartLen >= curIdx {
-		param.Description = strings.Join(retParts[curIdx:], " ")
+	return true, nil
+}
+
+// typedValue converts the string `value` to the go type matching the swagger type `swaggerType`
+func typedValue(swaggerType string, value string) (typed interface{}, err error) {
+
+	switch swaggerType {
+	case SwaggerTypeInt:
+		typed, err = strconv.ParseInt(value, 10, 64)
+	case SwaggerTypeFloat:
+		typed, err = strconv.ParseFloat(value, 64)
+	case SwaggerTypeBool:
+		typed, err = strconv.ParseBool(value)
+	default:
+		typed = value
+	}
+
+	if err != nil {
+		err = fmt.Errorf("Invalid %s value '%s'", swaggerType, value)
+	}
+
+	return
+}
+
+// typedDefault converts the default value of `param` to the go type matching its swagger type
+// The default of an array param is split by its collection format, and each value is typed with the type of its items
+func typedDefault(param Param) (typed interface{}, err error) {
+
+	if param.Type != SwaggerTypeArray {
+		return typedValue(param.Type, param.Default)
+	}
+
+	values := []interface{}{}
+
+	for _, value := range strings.Split(param.Default, CollectionFormatSeparators[param.CollectionFormat]) {
+		var typedItem interface{}
+		if typedItem, err = typedValue(param.ItemsType, value); err != nil {
+			return
+		}
+		values = append(values, typedItem)
+	}
+
+	return values, nil
+}
+
+// swaggerType converts a go type to its swagger equivalent
+// Types that are not go primitives (e.g. model names) are returned as is
+func swaggerType(goType string) string {
+	switch {
+	case len(goType) >= 3 && goType[0:3] == GoTypeInt:
+		return SwaggerTypeInt
+	case goType == GoTypeBool:
+		return SwaggerTypeBool
+	case goType == GoTypeString:
+		return SwaggerTypeString
+	case len(goType) >= 5 && goType[0:5] == GoTypeFloat:
+		return SwaggerTypeFloat
+	}
+	return goType
+}
+
+// ParseResponseKey parses a response code (e.g. `200`) or `default`
+func ParseResponseKey(key string) (responseCode int, isDefault bool, err error) {
+
+	if key == TagArgDefaultResponse {
+		isDefault = true
+		return
+	}
+
+	responseCode, err = strconv.Atoi(key)
+
+	if err != nil || responseCode < 100 || responseCode > 599 {
+		err = fmt.Errorf("Invalid response code '%s'", key)
 	}
 
 	return
 }
 
+// Key returns the key of the response in a swagger responses object
+func (r Response) Key() string {
+	if r.IsDefault {
+		return TagArgDefaultResponse
+	}
+	return strconv.Itoa(r.ResponseCode)
+}
+
 // ParseRouteResponse parses a route's response tag (@return)
-// Example: @return 200 Foo Returns a Foo object
+// Examples: @return 200 Foo Returns a Foo object, @return default ErrorObj Unexpected error
 func ParseRouteResponse(ret string) (response Response, err error) {
 	retParts := strings.Fields(ret)
 
 	retPartLen := len(retParts)
 
-	responseInt, _ := strconv.Atoi(retParts[0])
+	if retPartLen == 0 {
+		err = errors.New("Response code cannot be empty")
+		return
+	}
 
-	response.ResponseCode = responseInt
+	response.ResponseCode, response.IsDefault, err = ParseResponseKey(retParts[0])
+
+	if err != nil {
+		return
+	}
 
 	if retPartLen > 1 {
 		response.SchemaRef = retParts[1]
 	}
 
+	// Reference to a reusable response (e.g. `@return 401 $Unauthorized`)
+	if strings.HasPrefix(response.SchemaRef, ReusablePrefix) {
+		response.Ref = response.SchemaRef[len(ReusablePrefix):]
+		response.SchemaRef = ""
+	}
+
 	if retPartLen > 2 {
 		response.Description = strings.Join(retParts[2:], " ")
 	}
@@ -230,13 +549,115 @@ func ParseRouteTag(ret string) (tags []string, err error) {
 		return
 	}
 
-	if strings.Index(ret, ",") > -1 {
-		tags = strings.Split(ret, ",")
-	} else {
-		tags = []string{
-			ret,
+	for _, tag := range strings.Split(ret, ",") {
+		if tag = strings.TrimSpace(tag); len(tag) > 0 {
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) == 0 {
+		err = errors.New("Tags cannot be empty")
+	}
+
+	return
+}
+
+// ParseRouteMimeTypes parses a route's @consumes or @produces tag
+// Example: @produces application/json,text/csv
+func ParseRouteMimeTypes(ret string) (mimeTypes []string, err error) {
+
+	for _, mimeType := range strings.FieldsFunc(ret, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		if !strings.Contains(mimeType, "/") {
+			err = fmt.Errorf("Invalid mime type '%s'", mimeType)
+			return
+		}
+		mimeTypes = append(mimeTypes, mimeType)
+	}
+
+	if len(mimeTypes) == 0 {
+		err = errors.New("Mime types cannot be empty")
+	}
+
+	return
+}
+
+// ParseRouteSecurity parses a route's security tag (@security)
+// Returns a nil requirement for `@security none`
+// Examples: @security bearerAuth, @security oauth2 read:users,write:users
+func ParseRouteSecurity(ret string) (requirement SecurityRequirement, err error) {
+
+	retParts := strings.Fields(ret)
+
+	if len(retParts) == 0 {
+		err = errors.New("Security scheme cannot be empty")
+		return
+	}
+
+	if retParts[0] == TagArgSecurityNone {
+		return
+	}
+
+	// Scopes go after the name of the scheme (e.g. `@security oauth2 read:users`)
+	if strings.ContainsAny(retParts[0], ":,") {
+		err = fmt.Errorf("Security scheme '%s' is not a valid scheme name", retParts[0])
+		return
+	}
+
+	scopes := []string{}
+	if len(retParts) > 1 {
+		for _, scope := range strings.Split(strings.Join(retParts[1:], ""), ",") {
+			if len(scope) > 0 {
+				scopes = append(scopes, scope)
+			}
 		}
 	}
 
+	requirement = SecurityRequirement{
+		retParts[0]: scopes,
+	}
+
+	return
+}
+
+// ParseRouteHeader parses a route's response header tag (@header)
+// Example: @header 201 Location string URL of the created resource
+func ParseRouteHeader(ret string) (header ResponseHeader, err error) {
+
+	retParts := strings.Fields(ret)
+
+	if len(retParts) < 3 {
+		err = fmt.Errorf("The tag @header is not in the correct format: '%s'", ret)
+		return
+	}
+
+	if _, _, err = ParseResponseKey(retParts[0]); err != nil {
+		return
+	}
+
+	header.ResponseKey = retParts[0]
+	header.Name = retParts[1]
+
+	if strings.HasPrefix(retParts[2], "[]") {
+		header.Type = SwaggerTypeArray
+		header.ItemsType = swaggerType(retParts[2][2:])
+	} else {
+		header.Type = swaggerType(retParts[2])
+	}
+
+	// Headers are primitives or arrays of primitives (e.g. `[]string`)
+	valueType := header.Type
+	if valueType == SwaggerTypeArray {
+		valueType = header.ItemsType
+	}
+
+	if !inArray(valueType, HeaderTypes) {
+		err = fmt.Errorf("Invalid type '%s' for header '%s' (headers can only be a string, int, float or bool, or an array of them)", retParts[2], header.Name)
+		return
+	}
+
+	if len(retParts) > 3 {
+		header.Description = strings.Join(retParts[3:], " ")
+	}
+
 	return
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -59,6 +60,54 @@ func TestParseRoute(t *testing.T) {
 
 }
 
+func TestParseRoute_Summary(t *testing.T) {
+
+	comments := []string{
+		"GetFoo gets a foo by its id. Foos are cached.",
+		"",
+		"@route GetFoo GET /foo",
+	}
+
+	route, _ := ParseRoute("GetFoo GET /foo", 0, "some/file/path", comments)
+
+	if route.Summary != "GetFoo gets a foo by its id." {
+		t.Errorf("ParseRoute should have returned a Route with Summary == '%s' (actually '%s')", "GetFoo gets a foo by its id.", route.Summary)
+	}
+
+	if route.Description != comments[0] {
+		t.Errorf("ParseRoute should have returned a Route with Description == '%s' (actually '%s')", comments[0], route.Description)
+	}
+
+	route, _ = ParseRoute("GetFoo GET /foo Returns a Foo object", 0, "some/file/path", []string{"@route GetFoo GET /foo Returns a Foo object"})
+
+	if route.Summary != "Returns a Foo object" || route.Description != "Returns a Foo object" {
+		t.Errorf("ParseRoute should have used the @route text as Summary and Description (actually '%s' and '%s')", route.Summary, route.Description)
+	}
+}
+
+func TestParseRoute_WithoutVerbAndPath(t *testing.T) {
+
+	route, err := ParseRoute("GetFoo Returns a Foo object", 0, "some/file/path", []string{})
+
+	if err != nil {
+		t.Errorf("ParseRoute should have returned a `nil` error (actually %s)", err.Error())
+	}
+
+	if route.OperationID != "GetFoo" {
+		t.Errorf("ParseRoute should have returned a Route with OperationID == '%s' (actually '%s')", "GetFoo", route.OperationID)
+	}
+
+	if route.Verb != "" || route.Path != "" {
+		t.Errorf("ParseRoute should have returned a Route without a verb or path (actually '%s' '%s')", route.Verb, route.Path)
+	}
+
+	route, _ = ParseRoute("GetFoo post", 0, "some/file/path", []string{})
+
+	if route.Verb != "POST" {
+		t.Errorf("ParseRoute should have returned a Route with Verb == '%s' (actually '%s')", "POST", route.Verb)
+	}
+}
+
 func TestParseRouteParam(t *testing.T) {
 
 	line := "foo int in:path optional This is the foo param"
@@ -85,6 +134,76 @@ func TestParseRouteParam(t *testing.T) {
 	}
 }
 
+func TestParseRouteParam_Options(t *testing.T) {
+
+	line := "status []string in:query optional collectionFormat:multi enum:active|inactive Filter by status"
+	param, err := ParseRouteParam(line)
+
+	if err != nil {
+		t.Errorf("ParseRouteParam should have a nil error (actually %s)", err.Error())
+	}
+
+	if param.Type != "array" || param.ItemsType != "string" {
+		t.Errorf("ParseRouteParam should have returned Param with Type == array of string (actually %s of %s)", param.Type, param.ItemsType)
+	}
+
+	if param.CollectionFormat != "multi" {
+		t.Errorf("ParseRouteParam should have returned Param with CollectionFormat == '%s' (actually '%s')", "multi", param.CollectionFormat)
+	}
+
+	if len(param.Enum) != 2 || param.Enum[0] != "active" || param.Enum[1] != "inactive" {
+		t.Errorf("ParseRouteParam should have returned Param with Enum == [active inactive] (actually %v)", param.Enum)
+	}
+
+	if param.Description != "Filter by status" {
+		t.Errorf("ParseRouteParam should have returned Param with Description == '%s' (actually '%s')", "Filter by status", param.Description)
+	}
+
+	line = "limit int optional default:20 min:1 max:100 format:int32 The number of results"
+	param, err = ParseRouteParam(line)
+
+	if err != nil {
+		t.Errorf("ParseRouteParam should have a nil error (actually %s)", err.Error())
+	}
+
+	if param.In != "query" {
+		t.Errorf("ParseRouteParam should have defaulted to In == '%s' (actually '%s')", "query", param.In)
+	}
+
+	if param.Default != "20" || param.Format != "int32" {
+		t.Errorf("ParseRouteParam should have returned Param with Default == 20 and Format == int32 (actually %s and %s)", param.Default, param.Format)
+	}
+
+	if param.Minimum == nil || *param.Minimum != 1 || param.Maximum == nil || *param.Maximum != 100 {
+		t.Errorf("ParseRouteParam should have returned Param with Minimum == 1 and Maximum == 100")
+	}
+
+	if param.Description != "The number of results" {
+		t.Errorf("ParseRouteParam should have returned Param with Description == '%s' (actually '%s')", "The number of results", param.Description)
+	}
+}
+
+func TestParseRouteParam_ShouldReturnErrorForInvalidDefault(t *testing.T) {
+
+	_, err := ParseRouteParam("limit int default:twenty The number of results")
+
+	if err == nil {
+		t.Errorf("ParseRouteParam should have returned an error (actually nil)")
+	}
+
+	_, err = ParseRouteParam("limit int collectionFormat:csv The number of results")
+
+	if err == nil {
+		t.Errorf("ParseRouteParam should have returned an error (actually nil)")
+	}
+
+	_, err = ParseRouteParam("ids []int default:1,two The ids")
+
+	if err == nil {
+		t.Errorf("ParseRouteParam should have returned an error for an array default with an invalid item (actually nil)")
+	}
+}
+
 func TestParseRouteParam_ShouldReturnError(t *testing.T) {
 	line := "foo int in:foo optional This is the foo param"
 	_, err := ParseRouteParam(line)
@@ -172,3 +291,248 @@ func TestParseRouteTag_ShouldReturnError(t *testing.T) {
 func shouldHave(f string, m string, a string, t *testing.T) {
 	t.Errorf("%s should have returned %s (actually %s)", f, m, a)
 }
+
+func TestParseRouteMimeTypes(t *testing.T) {
+
+	mimeTypes, err := ParseRouteMimeTypes("application/json,text/csv")
+
+	if err != nil {
+		t.Errorf("ParseRouteMimeTypes should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if len(mimeTypes) != 2 || mimeTypes[0] != "application/json" || mimeTypes[1] != "text/csv" {
+		t.Errorf("ParseRouteMimeTypes should have returned [application/json text/csv] (actually %v)", mimeTypes)
+	}
+}
+
+func TestParseRouteMimeTypes_ShouldReturnError(t *testing.T) {
+
+	_, err := ParseRouteMimeTypes("csv")
+
+	if err == nil {
+		t.Errorf("ParseRouteMimeTypes should have returned an error (actually nil)")
+	}
+}
+
+func TestParseRouteSecurity(t *testing.T) {
+
+	requirement, err := ParseRouteSecurity("oauth2 read:users,write:users")
+
+	if err != nil {
+		t.Errorf("ParseRouteSecurity should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if scopes, ok := requirement["oauth2"]; !ok || len(scopes) != 2 || scopes[0] != "read:users" || scopes[1] != "write:users" {
+		t.Errorf("ParseRouteSecurity should have returned oauth2 with scopes [read:users write:users] (actually %v)", requirement)
+	}
+
+	requirement, _ = ParseRouteSecurity("bearerAuth")
+
+	if scopes, ok := requirement["bearerAuth"]; !ok || scopes == nil || len(scopes) != 0 {
+		t.Errorf("ParseRouteSecurity should have returned bearerAuth with an empty scope list (actually %v)", requirement)
+	}
+
+	requirement, _ = ParseRouteSecurity("none")
+
+	if requirement != nil {
+		t.Errorf("ParseRouteSecurity should have returned a nil requirement for `none` (actually %v)", requirement)
+	}
+
+	if _, err = ParseRouteSecurity("read:users"); err == nil {
+		t.Errorf("ParseRouteSecurity should have returned an error for a scope without a scheme (actually nil)")
+	}
+}
+
+func TestGetRoutes_InvalidSecurity(t *testing.T) {
+
+	lines := []string{
+		"// @route GetUsers GET /users",
+		"// @security",
+		"// @security read:users",
+		"func GetUsers() {}",
+		"",
+		"// @route GetStatus GET /status",
+		"// @security none",
+		"func GetStatus() {}",
+	}
+
+	result := ParseLines(lines, "users.go", false)
+
+	if routes := result.Routes["/users"]; len(routes) != 1 || routes[0].Security != nil {
+		t.Errorf("ParseLines should have left the security of GetUsers unset, as none of its @security tags are valid (actually %v)", routes)
+	}
+
+	if len(result.Diagnostics) != 2 {
+		t.Errorf("ParseLines should have reported 2 invalid @security tags (actually %v)", result.Diagnostics)
+	}
+
+	if routes := result.Routes["/status"]; len(routes) != 1 || routes[0].Security == nil || len(routes[0].Security) != 0 {
+		t.Errorf("ParseLines should have disabled the security of GetStatus (actually %v)", routes)
+	}
+}
+
+func TestGetRoutes_DuplicateTags(t *testing.T) {
+
+	lines := []string{
+		"// @route GetUsers GET /users",
+		"// @tag users,admin",
+		"// @tags orders,users",
+		"func GetUsers() {}",
+	}
+
+	result := ParseLines(lines, "users.go", false)
+
+	routes := result.Routes["/users"]
+
+	if len(routes) != 1 || strings.Join(routes[0].Tags, ",") != "users,admin,orders" {
+		t.Errorf("ParseLines should have returned the tags users, admin and orders (actually %v)", routes)
+	}
+}
+
+func TestParseRouteResponse_Default(t *testing.T) {
+
+	response, err := ParseRouteResponse("default ErrorObj Unexpected error")
+
+	if err != nil {
+		t.Errorf("ParseRouteResponse should have returned error == nil (actually '%s')", err.Error())
+	}
+
+	if !response.IsDefault || response.Key() != "default" {
+		t.Errorf("ParseRouteResponse should have returned the default response (actually '%s')", response.Key())
+	}
+
+	if response.SchemaRef != "ErrorObj" {
+		t.Errorf("ParseRouteResponse should have returned SchemaRef == '%s' (actually '%s')", "ErrorObj", response.SchemaRef)
+	}
+}
+
+func TestParseRouteResponse_ShouldReturnError(t *testing.T) {
+
+	_, err := ParseRouteResponse("ok Foo Returns a Foo object")
+
+	if err == nil {
+		t.Errorf("ParseRouteResponse should have returned an error (actually nil)")
+	}
+}
+
+func TestParseRouteHeader(t *testing.T) {
+
+	header, err := ParseRouteHeader("201 Location string URL of the created resource")
+
+	if err != nil {
+		t.Errorf("ParseRouteHeader should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if header.ResponseKey != "201" || header.Name != "Location" || header.Type != "string" {
+		t.Errorf("ParseRouteHeader should have returned 201 Location string (actually %s %s %s)", header.ResponseKey, header.Name, header.Type)
+	}
+
+	if header.Description != "URL of the created resource" {
+		t.Errorf("ParseRouteHeader should have returned Description == '%s' (actually '%s')", "URL of the created resource", header.Description)
+	}
+
+	header, _ = ParseRouteHeader("200 X-RateLimit-Remaining int")
+
+	if header.Type != "integer" {
+		t.Errorf("ParseRouteHeader should have returned Type == '%s' (actually '%s')", "integer", header.Type)
+	}
+
+	header, _ = ParseRouteHeader("200 Link []string Pagination links")
+
+	if header.Type != "array" || header.ItemsType != "string" {
+		t.Errorf("ParseRouteHeader should have returned an array of strings (actually %s of %s)", header.Type, header.ItemsType)
+	}
+}
+
+func TestParseRouteHeader_ShouldReturnErrorForInvalidType(t *testing.T) {
+
+	for _, ret := range []string{"201 Location User", "201 Location []User", "201 Location file"} {
+		if _, err := ParseRouteHeader(ret); err == nil {
+			t.Errorf("ParseRouteHeader should have returned an error for '%s' (actually nil)", ret)
+		}
+	}
+}
+
+func TestParseRouteParam_FormData(t *testing.T) {
+
+	param, err := ParseRouteParam("name string in:form The name of the upload")
+
+	if err != nil {
+		t.Errorf("ParseRouteParam should have a nil error (actually %s)", err.Error())
+	}
+
+	if param.In != "formData" {
+		t.Errorf("ParseRouteParam should have returned Param with In == '%s' (actually '%s')", "formData", param.In)
+	}
+
+	param, _ = ParseRouteParam("upload file The uploaded file")
+
+	if param.Type != "file" || param.In != "formData" {
+		t.Errorf("ParseRouteParam should have returned a file Param in formData (actually %s in %s)", param.Type, param.In)
+	}
+
+	_, err = ParseRouteParam("upload file in:query The uploaded file")
+
+	if err == nil {
+		t.Errorf("ParseRouteParam should have returned an error for a file param in the query (actually nil)")
+	}
+
+	_, err = ParseRouteParam("uploads []file in:form The uploaded files")
+
+	if err == nil {
+		t.Errorf("ParseRouteParam should have returned an error for an array of files (actually nil)")
+	}
+}
+
+func TestParseRouteParam_Ref(t *testing.T) {
+
+	param, err := ParseRouteParam("$RequestID")
+
+	if err != nil {
+		t.Errorf("ParseRouteParam should have a nil error (actually %s)", err.Error())
+	}
+
+	if param.Ref != "RequestID" {
+		t.Errorf("ParseRouteParam should have returned Param with Ref == '%s' (actually '%s')", "RequestID", param.Ref)
+	}
+}
+
+func TestParseRouteResponse_Ref(t *testing.T) {
+
+	response, err := ParseRouteResponse("401 $Unauthorized")
+
+	if err != nil {
+		t.Errorf("ParseRouteResponse should have returned error == nil (actually '%s')", err.Error())
+	}
+
+	if response.Ref != "Unauthorized" || response.SchemaRef != "" {
+		t.Errorf("ParseRouteResponse should have returned Response with Ref == '%s' (actually '%s')", "Unauthorized", response.Ref)
+	}
+}
+
+func TestParseRouteTag_TrimsSpaces(t *testing.T) {
+
+	tags, _ := ParseRouteTag("foo, bar")
+
+	if len(tags) != 2 || tags[1] != "bar" {
+		t.Errorf("ParseRouteTag should have returned [foo bar] (actually %v)", tags)
+	}
+}
+
+func TestParseRoute_DescriptionFile(t *testing.T) {
+
+	comments := []string{
+		"@route CreateOrder POST /orders",
+		"@description file:docs/api/create-order.md",
+	}
+
+	route, _ := ParseRoute("CreateOrder POST /orders", 0, "some/file/path", comments)
+
+	if route.Description != "file:docs/api/create-order.md" {
+		t.Errorf("ParseRoute should have returned a Route with Description == '%s' (actually '%s')", "file:docs/api/create-order.md", route.Description)
+	}
+
+	if route.Summary != "" {
+		t.Errorf("ParseRoute should not have summarized a description file (actually '%s')", route.Summary)
+	}
+}
@@ -0,0 +1,541 @@
+/**
+ * Sources
+ */
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ArchiveExtensions are the extensions of the archives that can be scanned instead of a source directory
+var ArchiveExtensions = []string{".zip", ".tar", ".tar.gz", ".tgz"}
+
+// osFS is the filesystem of the operating system
+// Unlike os.DirFS, names are OS paths (relative to the working directory, or absolute)
+type osFS struct{}
+
+// Open opens a file or directory
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+// ReadFile reads a whole file
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+// ReadDir returns the entries of a directory, sorted by name
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// Stat returns the info of a file or directory
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// IsOSFS checks if `fsys` is the filesystem of the operating system (a nil filesystem is)
+func IsOSFS(fsys fs.FS) bool {
+	_, ok := fsys.(osFS)
+	return fsys == nil || ok
+}
+
+// OpenSources returns the filesystem to read the sources from, along with the source directories in it
+// The sources are read from the OS filesystem (a nil filesystem) unless the only source is an archive (see ReadArchive),
+// or `rev` is set, in which case the source directories are read from that git revision (see GitRevisionFS)
+func OpenSources(sourceDirs []string, rev string) (fsys fs.FS, rootPaths []string, err error) {
+
+	for _, sourceDir := range sourceDirs {
+		if IsArchive(sourceDir) && (len(sourceDirs) > 1 || len(rev) > 0) {
+			return nil, nil, fmt.Errorf("The archive %s must be the only source, and cannot be used with -rev", sourceDir)
+		}
+	}
+
+	if len(rev) > 0 {
+
+		repoDir, prefix, gitErr := GitPrefix(".")
+		if gitErr != nil {
+			return nil, nil, gitErr
+		}
+
+		// Source directories are relative to the working directory, which is `prefix` in the repository
+		for _, sourceDir := range sourceDirs {
+			rootPath := path.Join(prefix, filepath.ToSlash(sourceDir))
+			if filepath.IsAbs(sourceDir) || !fs.ValidPath(rootPath) {
+				return nil, nil, fmt.Errorf("The source directory %s is not inside the git repository %s", sourceDir, repoDir)
+			}
+			rootPaths = append(rootPaths, rootPath)
+		}
+
+		revFS, revErr := GitRevisionFS(repoDir, rev)
+		if revErr != nil {
+			return nil, nil, revErr
+		}
+
+		return revFS, rootPaths, nil
+	}
+
+	if len(sourceDirs) == 1 && IsArchive(sourceDirs[0]) {
+
+		archiveFS, archiveErr := ReadArchive(sourceDirs[0])
+		if archiveErr != nil {
+			return nil, nil, archiveErr
+		}
+
+		return archiveFS, []string{"."}, nil
+	}
+
+	return nil, sourceDirs, nil
+}
+
+// sourceFS returns the filesystem the sources are read from
+func (s *Swaggerf) sourceFS() fs.FS {
+
+	if s.FS == nil {
+		return osFS{}
+	}
+
+	return s.FS
+}
+
+// MemFS is a read only filesystem kept in memory, holding the sources read from an archive or a git revision
+// The content of a file is only loaded when the file is read
+type MemFS struct {
+	files map[string]memEntry
+	dirs  map[string]map[string]bool // the names of the entries of each directory
+}
+
+// memEntry is a file of a MemFS
+type memEntry struct {
+	size    int64
+	modTime time.Time
+	load    func() ([]byte, error)
+}
+
+// NewMemFS returns an empty filesystem
+func NewMemFS() *MemFS {
+	return &MemFS{
+		files: map[string]memEntry{},
+		dirs:  map[string]map[string]bool{".": {}},
+	}
+}
+
+// AddFile adds a file (and the directories it is in) to the filesystem. `load` returns the content of the file
+func (m *MemFS) AddFile(name string, size int64, modTime time.Time, load func() ([]byte, error)) {
+
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	m.files[name] = memEntry{size, modTime, load}
+
+	for dir, base := path.Dir(name), path.Base(name); ; dir, base = path.Dir(dir), path.Base(dir) {
+		if m.dirs[dir] == nil {
+			m.dirs[dir] = map[string]bool{}
+		}
+		m.dirs[dir][base] = true
+		if dir == "." {
+			break
+		}
+	}
+}
+
+// Open opens a file or directory
+func (m *MemFS) Open(name string) (fs.File, error) {
+
+	info, err := m.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		entries, _ := m.ReadDir(name)
+		return &memDir{info: info, entries: entries}, nil
+	}
+
+	content, err := m.files[name].load()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &memFile{info: info, reader: bytes.NewReader(content)}, nil
+}
+
+// ReadFile reads a whole file
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+
+	entry, ok := m.files[name]
+	if !ok || !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	content, err := entry.load()
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+
+	// Callers are allowed to modify the content they are returned
+	return append([]byte{}, content...), nil
+}
+
+// ReadDir returns the entries of a directory, sorted by name
+func (m *MemFS) ReadDir(name string) (entries []fs.DirEntry, err error) {
+
+	names, ok := m.dirs[name]
+	if !ok || !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	for entryName := range names {
+		info, _ := m.Stat(path.Join(name, entryName))
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return
+}
+
+// Stat returns the info of a file or directory
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if entry, ok := m.files[name]; ok {
+		return memInfo{path.Base(name), entry.size, 0444, entry.modTime}, nil
+	}
+
+	if _, ok := m.dirs[name]; ok {
+		return memInfo{path.Base(name), 0, fs.ModeDir | 0555, time.Time{}}, nil
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// memInfo describes a file or directory of a MemFS
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() interface{}   { return nil }
+
+// memFile is an open file of a MemFS
+type memFile struct {
+	info   fs.FileInfo
+	reader *bytes.Reader
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Read(b []byte) (int, error) { return f.reader.Read(b) }
+func (f *memFile) Close() error               { return nil }
+
+// memDir is an open directory of a MemFS
+type memDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read(b []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: fs.ErrInvalid}
+}
+
+// ReadDir returns the next `count` entries of the directory (all the remaining ones if `count` is not positive)
+func (d *memDir) ReadDir(count int) ([]fs.DirEntry, error) {
+
+	remaining := d.entries[d.offset:]
+
+	if count > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if count > 0 && count < len(remaining) {
+		remaining = remaining[0:count]
+	}
+
+	d.offset = d.offset + len(remaining)
+
+	return remaining, nil
+}
+
+// IsArchive checks if a source path is an archive (see ArchiveExtensions)
+func IsArchive(sourcePath string) bool {
+
+	for _, extension := range ArchiveExtensions {
+		if strings.HasSuffix(strings.ToLower(sourcePath), extension) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadArchive reads the files of a zip or (gzipped) tar archive into memory
+func ReadArchive(archivePath string) (*MemFS, error) {
+
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		return readZipArchive(archivePath)
+	}
+
+	return readTarArchive(archivePath)
+}
+
+// readZipArchive reads the files of a zip archive, whose content is only decompressed when a file is read
+func readZipArchive(archivePath string) (*MemFS, error) {
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fsys := NewMemFS()
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		file := file
+		fsys.AddFile(file.Name, int64(file.UncompressedSize64), file.Modified, func() ([]byte, error) {
+			content, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer content.Close()
+			return ioutil.ReadAll(content)
+		})
+	}
+
+	return fsys, nil
+}
+
+// readTarArchive reads the regular files of a tar archive, gzipped if its extension is .tar.gz or .tgz
+func readTarArchive(archivePath string) (*MemFS, error) {
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+
+	if lowerPath := strings.ToLower(archivePath); strings.HasSuffix(lowerPath, ".gz") || strings.HasSuffix(lowerPath, ".tgz") {
+		gzipReader, gzipErr := gzip.NewReader(file)
+		if gzipErr != nil {
+			return nil, fmt.Errorf("%s is not a gzipped tar archive: %s", archivePath, gzipErr.Error())
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	fsys := NewMemFS()
+	tarReader := tar.NewReader(reader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s could not be read: %s", archivePath, err.Error())
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+
+		fsys.AddFile(header.Name, int64(len(content)), header.ModTime, func() ([]byte, error) {
+			return content, nil
+		})
+	}
+
+	return fsys, nil
+}
+
+// GitRevisionFS returns the files of a git revision (e.g. `main`, `v1.2.0`, `HEAD~3`) of the repository in `repoDir`,
+// without checking it out. The content of a file is read from the git object database when the file is read,
+// through a single `git cat-file --batch` process (see gitBlobReader)
+func GitRevisionFS(repoDir string, rev string) (*MemFS, error) {
+
+	output, err := runGit(repoDir, "ls-tree", "-r", "-l", "-z", "--full-tree", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	fsys := NewMemFS()
+	blobs := &gitBlobReader{repoDir: repoDir}
+
+	// Each entry is `<mode> <type> <object> <size>\t<path>`
+	for _, entry := range strings.Split(string(output), "\x00") {
+
+		tabIdx := strings.Index(entry, "\t")
+		if tabIdx < 0 {
+			continue
+		}
+
+		fields := strings.Fields(entry[0:tabIdx])
+		// Submodules and symlinks are not scanned
+		if len(fields) != 4 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+
+		object := fields[2]
+		size, _ := strconv.ParseInt(fields[3], 10, 64)
+
+		fsys.AddFile(entry[tabIdx+1:], size, time.Time{}, func() ([]byte, error) {
+			return blobs.Read(object)
+		})
+	}
+
+	return fsys, nil
+}
+
+// gitBlobReader reads blobs from the object database of a git repository through a `git cat-file --batch` process,
+// which is started on the first read and exits along with swagger-gen (when its input is closed)
+// Reads are serialized, so files can be read concurrently
+type gitBlobReader struct {
+	repoDir string
+	mutex   sync.Mutex
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+	err     error
+}
+
+// Read returns the content of the blob `object`
+func (r *gitBlobReader) Read(object string) ([]byte, error) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.stdout == nil && r.err == nil {
+		r.err = r.start()
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if _, err := io.WriteString(r.stdin, object+"\n"); err != nil {
+		r.err = fmt.Errorf("git cat-file --batch failed in %s: %s", r.repoDir, err.Error())
+		return nil, r.err
+	}
+
+	// The content is preceded by `<object> <type> <size>` (or `<object> missing`) and followed by a newline
+	header, err := r.stdout.ReadString('\n')
+	if err != nil {
+		r.err = fmt.Errorf("git cat-file --batch failed in %s: %s", r.repoDir, err.Error())
+		return nil, r.err
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("git object %s could not be read: %s", object, strings.TrimSpace(header))
+	}
+
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		r.err = fmt.Errorf("git cat-file --batch returned an invalid header '%s'", strings.TrimSpace(header))
+		return nil, r.err
+	}
+
+	content := make([]byte, size+1)
+	if _, err = io.ReadFull(r.stdout, content); err != nil {
+		r.err = fmt.Errorf("git cat-file --batch failed in %s: %s", r.repoDir, err.Error())
+		return nil, r.err
+	}
+
+	return content[0:size], nil
+}
+
+// start starts the `git cat-file --batch` process
+func (r *gitBlobReader) start() (err error) {
+
+	command := exec.Command("git", "cat-file", "--batch")
+	command.Dir = r.repoDir
+
+	if r.stdin, err = command.StdinPipe(); err != nil {
+		return
+	}
+
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return
+	}
+
+	if err = command.Start(); err != nil {
+		return fmt.Errorf("git cat-file --batch failed in %s: %s", r.repoDir, err.Error())
+	}
+
+	r.stdout = bufio.NewReader(stdout)
+
+	return
+}
+
+// GitPrefix returns the root of the git repository that `dir` is in,
+// along with the path of `dir` relative to it (e.g. `cmd/api/`)
+func GitPrefix(dir string) (repoDir string, prefix string, err error) {
+
+	output, err := runGit(dir, "rev-parse", "--show-toplevel", "--show-prefix")
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	repoDir = lines[0]
+	if len(lines) > 1 {
+		prefix = lines[1]
+	}
+
+	return
+}
+
+// runGit runs a git command in `dir`, returning its output
+func runGit(dir string, args ...string) ([]byte, error) {
+
+	command := exec.Command("git", args...)
+	command.Dir = dir
+
+	stderr := bytes.Buffer{}
+	command.Stderr = &stderr
+
+	output, err := command.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed in %s: %s %s", strings.Join(args, " "), dir, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+
+	return output, nil
+}
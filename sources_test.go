@@ -0,0 +1,174 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// testSources are the files of the archives and git revisions the tests read
+var testSources = map[string]string{
+	"swagger-meta.json":     `{"swagger": "2.0"}`,
+	"api/users.go":          "package api\n\n// @route GetUsers GET /users\n// @return 200 User\nfunc GetUsers() {}\n",
+	"models/user.go":        "package models\n\n// @model User\ntype User struct {\n\tName string `json:\"name\"`\n}\n",
+	"models/.swaggerignore": "mocks/\n",
+	"models/mocks/mock.go":  "package mocks\n\n// @model Mock\ntype Mock struct {\n}\n",
+}
+
+func TestMemFS(t *testing.T) {
+
+	fsys := NewMemFS()
+	for name, content := range testSources {
+		content := []byte(content)
+		fsys.AddFile(name, int64(len(content)), time.Time{}, func() ([]byte, error) {
+			return content, nil
+		})
+	}
+
+	if err := fstest.TestFS(fsys, "swagger-meta.json", "api/users.go", "models/mocks/mock.go"); err != nil {
+		t.Errorf("MemFS should have behaved as a filesystem (actually %s)", err.Error())
+	}
+}
+
+func TestReadArchive(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// tar.gz
+	tarBuffer := bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(&tarBuffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range testSources {
+		tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tarWriter.Write([]byte(content))
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+	ioutil.WriteFile(filepath.Join(dir, "sources.tar.gz"), tarBuffer.Bytes(), 0644)
+
+	// zip
+	zipBuffer := bytes.Buffer{}
+	zipWriter := zip.NewWriter(&zipBuffer)
+	for name, content := range testSources {
+		file, _ := zipWriter.Create(name)
+		file.Write([]byte(content))
+	}
+	zipWriter.Close()
+	ioutil.WriteFile(filepath.Join(dir, "sources.zip"), zipBuffer.Bytes(), 0644)
+
+	for _, archiveName := range []string{"sources.tar.gz", "sources.zip"} {
+
+		fsys, rootPaths, err := OpenSources([]string{filepath.Join(dir, archiveName)}, "")
+		if err != nil {
+			t.Fatalf("OpenSources should have read %s (actually %s)", archiveName, err.Error())
+		}
+
+		s := Swaggerf{FS: fsys}
+		files, err := s.SourceFiles(rootPaths)
+
+		if err != nil || strings.Join(files, ",") != "api/users.go,models/user.go" {
+			t.Errorf("SourceFiles should have returned api/users.go,models/user.go from %s (actually %v, %v)", archiveName, files, err)
+		}
+
+		if err = s.BuildSwagger(rootPaths...); err != nil || len(s.Swagger.Paths) != 1 || len(s.Swagger.Definitions) != 1 {
+			t.Errorf("BuildSwagger should have built 1 path and 1 definition from %s (actually %v, %v)", archiveName, s.Swagger, err)
+		}
+	}
+
+	if _, _, err := OpenSources([]string{filepath.Join(dir, "sources.zip"), "."}, ""); err == nil {
+		t.Errorf("OpenSources should have returned an error for an archive that is not the only source")
+	}
+}
+
+func TestGitRevisionFS(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir, err := ioutil.TempDir("", "swagger-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repoDir)
+
+	for name, content := range testSources {
+		os.MkdirAll(filepath.Dir(filepath.Join(repoDir, name)), 0755)
+		ioutil.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644)
+	}
+
+	git := func(args ...string) {
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if _, err := runGit(repoDir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add users")
+
+	// The working tree no longer has the route
+	os.Remove(filepath.Join(repoDir, "api", "users.go"))
+
+	fsys, err := GitRevisionFS(repoDir, "HEAD")
+	if err != nil {
+		t.Fatalf("GitRevisionFS should have returned a nil error (actually %s)", err.Error())
+	}
+
+	content, err := fsys.ReadFile("api/users.go")
+	if err != nil || string(content) != testSources["api/users.go"] {
+		t.Errorf("GitRevisionFS should have read api/users.go from the revision (actually '%s', %v)", content, err)
+	}
+
+	// Files are read concurrently through the same git process
+	names := []string{"swagger-meta.json", "models/user.go", "models/mocks/mock.go", "models/.swaggerignore"}
+	contents := make([]string, len(names))
+	wait := sync.WaitGroup{}
+	for i, name := range names {
+		wait.Add(1)
+		go func(i int, name string) {
+			defer wait.Done()
+			content, _ := fsys.ReadFile(name)
+			contents[i] = string(content)
+		}(i, name)
+	}
+	wait.Wait()
+
+	for i, name := range names {
+		if contents[i] != testSources[name] {
+			t.Errorf("GitRevisionFS should have read %s from the revision (actually '%s')", name, contents[i])
+		}
+	}
+
+	blobs := &gitBlobReader{repoDir: repoDir}
+	if _, err = blobs.Read(strings.Repeat("0", 40)); err == nil {
+		t.Errorf("gitBlobReader.Read should have returned an error for a missing object")
+	}
+	if content, err := blobs.Read("HEAD:api/users.go"); err != nil || string(content) != testSources["api/users.go"] {
+		t.Errorf("gitBlobReader.Read should have read HEAD:api/users.go after a missing object (actually '%s', %v)", content, err)
+	}
+
+	s := Swaggerf{FS: fsys}
+	if err = s.BuildSwagger("api", "models"); err != nil || len(s.Swagger.Paths) != 1 {
+		t.Errorf("BuildSwagger should have built 1 path from the revision (actually %v, %v)", s.Swagger.Paths, err)
+	}
+
+	if _, err = GitRevisionFS(repoDir, "missing-branch"); err == nil {
+		t.Errorf("GitRevisionFS should have returned an error for an unknown revision")
+	}
+}
@@ -4,6 +4,8 @@
 
 package main
 
+import "reflect"
+
 type Swagger struct {
 	Swagger             string                     `json:"swagger"`
 	Info                SwaggerInfo                `json:"info"`
@@ -11,9 +13,14 @@ type Swagger struct {
 	BasePath            string                     `json:"basePath"`
 	Tags                []Tag                      `json:"tags"`
 	Schemes             []string                   `json:"schemes"`
+	Consumes            []string                   `json:"consumes,omitempty"`
+	Produces            []string                   `json:"produces,omitempty"`
 	Paths               map[string]map[string]Path `json:"paths"`
 	SecurityDefinitions map[string]interface{}     `json:"securityDefinitions,omitempty"`
+	Security            []SecurityRequirement      `json:"security,omitempty"`
 	Definitions         map[string]ModelDefinition `json:"definitions"`
+	Parameters          map[string]Parameter       `json:"parameters,omitempty"`
+	Responses           map[string]PathResponse    `json:"responses,omitempty"`
 }
 
 type SwaggerInfo struct {
@@ -34,16 +41,44 @@ type Path struct {
 	Parameters  []Parameter             `json:"parameters,omitempty"`
 	Responses   map[string]PathResponse `json:"responses"`
 	Tags        []string                `json:"tags,omitempty"`
+	Security    *[]SecurityRequirement  `json:"security,omitempty"`
+	Deprecated  bool                    `json:"deprecated,omitempty"`
+	XSunset     string                  `json:"x-sunset,omitempty"`
+	Extensions  map[string]interface{}  `json:"-"` // vendor extensions (@x-...)
 }
 
+// SecurityRequirement maps the name of a security scheme to the scopes it requires
+type SecurityRequirement map[string][]string
+
 // Parameter represents a parameter in a swagger specification
 type Parameter struct {
-	In          string            `json:"in,omitempty"`
-	Name        string            `json:"name,omitempty"`
-	Description string            `json:"description,omitempty"`
-	Required    bool              `json:"required"`
-	Schema      map[string]string `json:"schema,omitempty"`
-	Type        string            `json:"type,omitempty"`
+	Ref              string            `json:"$ref,omitempty"`
+	In               string            `json:"in,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Required         bool              `json:"required"`
+	Schema           map[string]string `json:"schema,omitempty"`
+	Type             string            `json:"type,omitempty"`
+	Format           string            `json:"format,omitempty"`
+	Items            *ParameterItems   `json:"items,omitempty"`
+	CollectionFormat string            `json:"collectionFormat,omitempty"`
+	Enum             []interface{}     `json:"enum,omitempty"`
+	Default          interface{}       `json:"default,omitempty"`
+	Minimum          *float64          `json:"minimum,omitempty"`
+	Maximum          *float64          `json:"maximum,omitempty"`
+	MinLength        *int              `json:"minLength,omitempty"`
+	MaxLength        *int              `json:"maxLength,omitempty"`
+	MinItems         *int              `json:"minItems,omitempty"`
+	MaxItems         *int              `json:"maxItems,omitempty"`
+	Pattern          string            `json:"pattern,omitempty"`
+	XExample         interface{}       `json:"x-example,omitempty"`
+}
+
+// ParameterItems describes the items of an array parameter
+type ParameterItems struct {
+	Type   string        `json:"type"`
+	Format string        `json:"format,omitempty"`
+	Enum   []interface{} `json:"enum,omitempty"`
 }
 
 type License struct {
@@ -79,7 +114,7 @@ type SrcFile struct {
 // }
 
 type Route struct {
-	Description string
+	Description string // markdown, or `file:path/to/description.md`
 	FilePath    string
 	LineNum     int
 	Verb        string
@@ -89,18 +124,75 @@ type Route struct {
 	Comments    []string
 	Params      []Param
 	Responses   []Response
+	Headers     []ResponseHeader
+	Examples    []RouteExample
 	Tags        []string
+	Consumes    []string
+	Produces    []string
+	Security    []SecurityRequirement // nil inherits the global security, empty (`@security none`) disables it
+	Handler     string                // name of the function the route's comment block is attached to
+	Deprecation Deprecation
+	Extensions  map[string]interface{} // vendor extensions (@x-...)
+}
+
+// Deprecation represents the @deprecated and @sunset tags of a route or model
+type Deprecation struct {
+	Deprecated bool
+	Reason     string
+	Sunset     string // YYYY-MM-DD
+}
+
+// RouterRoute represents a route registration call found in router setup code (e.g. `r.Get("/foo", h.GetFoo)`)
+type RouterRoute struct {
+	FilePath string
+	LineNum  int
+	Verb     string // empty if the registration matches any verb
+	Path     string
+	Handlers []string // names of the functions passed to the registration call
 }
 
 type Response struct {
 	ResponseCode int
+	IsDefault    bool // `@return default ...` describes every response code that is not declared
 	Description  string
 	SchemaRef    string // sets `type: "array"` if prefixed with `[]`
+	Ref          string // name of a reusable response (`@return 401 $Unauthorized`)
+}
+
+// Reusables represents the reusable parameters (@paramdef) and responses (@responsedef) declared in a file
+type Reusables struct {
+	Params    map[string]Param
+	Responses map[string]Response
+}
+
+// RouteExample represents an example request body or response of a route (@example)
+type RouteExample struct {
+	Target string // `body`, a response code or `default`
+	Value  string // JSON, or `file:path/to/example.json`
+}
+
+// ResponseHeader represents a header returned with a route's response (@header)
+type ResponseHeader struct {
+	ResponseKey string // response code or `default`
+	Name        string
+	Type        string
+	ItemsType   string // type of the items of an array (`[]string`) header
+	Description string
 }
 
 type PathResponse struct {
-	Description string     `json:"description"`
-	Schema      PathSchema `json:"schema,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Description string                 `json:"description"`
+	Schema      PathSchema             `json:"schema,omitempty"`
+	Headers     map[string]Header      `json:"headers,omitempty"`
+	Examples    map[string]interface{} `json:"examples,omitempty"`
+}
+
+// Header represents a header in a swagger response
+type Header struct {
+	Type        string          `json:"type"`
+	Items       *ParameterItems `json:"items,omitempty"`
+	Description string          `json:"description,omitempty"`
 }
 
 type PathSchema struct {
@@ -110,19 +202,28 @@ type PathSchema struct {
 }
 
 type Param struct {
-	Name        string
-	Description string
-	Required    bool
-	Produces    string
-	Type        string
-	In          string // query || path
+	Ref              string // name of a reusable parameter (`@param $RequestID`)
+	Name             string
+	Description      string
+	Required         bool
+	Produces         string
+	Type             string
+	ItemsType        string // type of the items of an array (`[]string`) param
+	In               string // query || path
+	CollectionFormat string // csv || ssv || tsv || pipes || multi
+	Enum             []string
+	Default          string
+	Minimum          *float64 // minimum for numbers, minLength for strings and minItems for arrays
+	Maximum          *float64 // maximum for numbers, maxLength for strings and maxItems for arrays
+	Pattern          string
+	Format           string
 }
 
 // Tag represents a swagger tag for grouping operations
 type Tag struct {
-	Name         string          `json:"name"`
-	Description  string          `json:"description"`
-	ExternalDocs TagExternalDocs `json:"externalDocs,omitempty"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description,omitempty"`
+	ExternalDocs *TagExternalDocs `json:"externalDocs,omitempty"`
 }
 
 // TagExternalDocs represents a description and url for external documentation on a tag
@@ -132,16 +233,33 @@ type TagExternalDocs struct {
 }
 
 type Model struct {
+	FilePath    string
+	LineNum     int
+	Name        string
+	Description string // markdown, or `file:path/to/description.md`
+	Example     string // JSON
+	Fields      []ModelField
+	Deprecation Deprecation
+	Extensions  map[string]interface{} // vendor extensions (@x-...)
+}
+
+// TagDef represents a tag definition (@tagdef) along with where it was found
+type TagDef struct {
+	Tag      Tag
 	FilePath string
 	LineNum  int
-	Name     string
-	Fields   []ModelField
 }
 
 type ModelField struct {
-	Name string
-	Type string
-	Ref  string
+	Name        string
+	Type        string
+	Ref         string
+	ItemsType   string // type of the items of an array of primitives (e.g. `[]string`)
+	Description string
+	Required    bool // set by a `required` binding or validate struct tag
+	StructTag   reflect.StructTag
+	Tags        map[string][]string // tags in the field's comments (e.g. `@example`, `@enum`, `@min`)
+	Embedded    bool                // an embedded struct (whose type is held by Ref), which promotes its fields
 }
 
 type Config struct {
@@ -152,15 +270,26 @@ type Config struct {
 }
 
 type ModelDefinition struct {
-	Type       string              `json:"type"`
-	Properties map[string]Property `json:"properties"`
+	Type        string                 `json:"type"`
+	Description string                 `json:"description,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Properties  map[string]Property    `json:"properties"`
+	Example     interface{}            `json:"example,omitempty"`
+	XDeprecated bool                   `json:"x-deprecated,omitempty"`
+	XSunset     string                 `json:"x-sunset,omitempty"`
+	Extensions  map[string]interface{} `json:"-"` // vendor extensions (@x-...)
 }
 
 type Property struct {
-	Type    string            `json:"type,omitempty"`
-	Format  string            `json:"format,omitempty"`
-	Enum    []string          `json:"enum,omitempty"`
-	Default interface{}       `json:"default,omitempty"`
-	Items   map[string]string `json:"items,omitempty"`
-	Ref     string            `json:"$ref,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	Enum        []interface{}          `json:"enum,omitempty"`
+	Default     interface{}            `json:"default,omitempty"`
+	Minimum     *float64               `json:"minimum,omitempty"`
+	Maximum     *float64               `json:"maximum,omitempty"`
+	Items       map[string]string      `json:"items,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Example     interface{}            `json:"example,omitempty"`
+	Extensions  map[string]interface{} `json:"-"` // vendor extensions (@x-...)
 }
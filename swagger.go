@@ -5,13 +5,78 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 type Swaggerf struct {
 	Swagger Swagger
+
+	// DiscoverRoutes fills in missing @route verbs and paths from router registration calls
+	DiscoverRoutes bool
+
+	// ExcludeDeprecated leaves deprecated routes out of the swagger file
+	ExcludeDeprecated bool
+
+	// ApplyParams are the names of reusable parameters added to every operation
+	ApplyParams []string
+
+	// ApplyResponses maps response codes to the names of reusable responses added to every operation
+	ApplyResponses map[string]string
+
+	// TagOrder is the order of the tags in the swagger file (see BuildTags)
+	TagOrder string
+
+	// GenerateExamples synthesizes examples for models and responses that do not have one
+	GenerateExamples bool
+
+	// Include are globs (relative to the root) the scanned files must match. All files are scanned if empty
+	Include []string
+
+	// Exclude are gitignore style patterns of files and directories not to scan (see FileFilter)
+	Exclude []string
+
+	// Packages are go list patterns (e.g. `./...`) used to find the files of each source root instead of walking it
+	Packages []string
+
+	// Languages are the names of the languages whose source files are scanned (see Language). Defaults to DefaultLanguages
+	Languages []string
+
+	// FS is the filesystem the sources are read from (see MemFS). The OS filesystem if nil
+	FS fs.FS
+
+	// Workers is the number of files parsed concurrently. Defaults to the number of CPUs
+	Workers int
+
+	// OpenAPI3 outputs the spec as an OpenAPI 3 document (see ConvertOpenAPI) instead of a swagger 2.0 spec
+	OpenAPI3 bool
+
+	// CachePath is the path of the build cache file (see Cache). The cache is not used if empty
+	CachePath string
+
+	// cache keeps the parse results in memory between builds (see Watch)
+	cache *Cache
+
+	// rootPath is the root of the source code being scanned
+	rootPath string
+
+	// referencedFiles are the files referenced with `file:` by the last build (see Watch)
+	referencedFiles []string
+}
+
+// Spec returns the document written to the outputs: the swagger spec, or its OpenAPI 3 conversion if OpenAPI3 is set
+func (s *Swaggerf) Spec() interface{} {
+
+	if s.OpenAPI3 {
+		return ConvertOpenAPI(s.Swagger)
+	}
+
+	return s.Swagger
 }
 
 func (s *Swaggerf) ParseSwaggerConfig(jsonBytes []byte) {
@@ -20,47 +85,70 @@ func (s *Swaggerf) ParseSwaggerConfig(jsonBytes []byte) {
 
 }
 
-// BuildSwagger builds a swagger file
-func (s *Swaggerf) BuildSwagger(rootPath string) {
+// BuildSwagger builds a swagger file from the source files in `rootPaths` (see SourceFiles)
+// The first root is the root of the project, which `file:` references are resolved from
+// Problems with the annotations are logged, and an error is only returned if the source files cannot be read
+func (s *Swaggerf) BuildSwagger(rootPaths ...string) error {
 
-	log.Printf("Building swagger file from path %s", rootPath)
-	sio := &Sio{}
-	sio.GetAllFilePaths(rootPath)
+	if len(rootPaths) == 0 {
+		return errors.New("No source directory to build the swagger file from")
+	}
 
-	allRoutes := map[string][]Route{}
-	allModels := map[string]Model{}
+	log.Printf("Building swagger file from path %s", strings.Join(rootPaths, ", "))
+	s.rootPath = rootPaths[0]
+	s.referencedFiles = []string{}
 
-	for _, path := range sio.TmpFiles {
+	for _, err := range ValidateSecurity(s.Swagger.Security, s.Swagger.SecurityDefinitions) {
+		log.Printf("Security Error: %s (swagger-meta.json)", err.Error())
+	}
 
-		lines, err := readLines(path)
-		if err != nil {
-			log.Fatal(err)
-		}
+	files, err := s.SourceFiles(rootPaths)
+	if err != nil {
+		return err
+	}
 
-		routeMap, _ := GetRoutes(lines, path)
+	// Files are parsed concurrently, then merged in the order they were found so the output is always the same
+	cache := s.cache
+	if cache == nil && len(s.CachePath) > 0 {
+		cache = ReadCache(s.CachePath)
+	}
 
-		for path, routes := range routeMap {
+	results := ParseFiles(s.FS, files, s.DiscoverRoutes, s.Workers, cache)
+	allRoutes, allModels, allRouterRoutes, allTagDefs, allReusables, err := mergeFileResults(results)
+	if err != nil {
+		return err
+	}
 
-			if _, ok := allRoutes[path]; !ok {
-				allRoutes[path] = []Route{}
+	if cache != nil {
+		cached := 0
+		for _, result := range results {
+			if result.Cached {
+				cached = cached + 1
 			}
+		}
+		log.Printf("Parsed %d files (%d unchanged files read from the cache)", len(results), cached)
 
-			for _, route := range routes {
-				allRoutes[path] = append(allRoutes[path], route)
+		cache.Update(results, s.DiscoverRoutes)
+
+		if len(s.CachePath) > 0 {
+			if err := cache.Write(s.CachePath); err != nil {
+				log.Printf("Cache Warning: the cache file %s could not be written: %s", s.CachePath, err.Error())
 			}
 		}
+	}
 
-		models, _ := GetModels(lines, path)
+	if s.DiscoverRoutes {
+		allRoutes = discoverRoutes(allRoutes, allRouterRoutes)
+	}
 
-		for name, model := range models {
-			allModels[name] = model
-		}
+	allRoutes = uniqueRoutes(allRoutes)
 
-		// for arrayModelName, modelName := range arrayModels {
-		// 	if _, ok := allArrayModels[arrayModelName]; !ok {
-		// 		allArrayModels[arrayModelName] = modelName
-		// 	}
-		// }
+	// Routes can only be documented once both their verb and path are known
+	if unresolved, ok := allRoutes[""]; ok {
+		for _, route := range unresolved {
+			log.Printf("Route Error: @route %s has no path (File: %s; Line: %d)", route.OperationID, route.FilePath, route.LineNum)
+		}
+		delete(allRoutes, "")
 	}
 
 	// Definitions (Models)
@@ -71,90 +159,678 @@ func (s *Swaggerf) BuildSwagger(rootPath string) {
 		definition := ModelDefinition{}
 
 		definition.Type = "object"
+		definition.Description = s.loadDescription(model.Description, model.FilePath, model.LineNum)
+		if note := model.Deprecation.Note(); len(note) > 0 {
+			definition.Description = strings.TrimSpace(definition.Description + "\n\n" + note)
+		}
+		definition.XDeprecated = model.Deprecation.Deprecated
+		definition.XSunset = model.Deprecation.Sunset
+		definition.Extensions = model.Extensions
 		definition.Properties = map[string]Property{}
 
 		for _, field := range model.Fields {
+
+			// The fields promoted by embedded structs are only expanded into params (see expandModelParams)
+			if field.Embedded {
+				continue
+			}
+
 			property := Property{}
 			property.Type = field.Type
+			property.Description = field.Description
 
 			if field.Type == "array" {
 
 				property.Type = "array"
 				property.Items = map[string]string{}
 
-				// TODO this does not support a simple array of strings
-				// if len(field.Ref) > 0 {
-				property.Items["$ref"] = "#/definitions/" + field.Ref
-				// }
+				if len(field.ItemsType) > 0 {
+					property.Items["type"] = field.ItemsType
+				} else {
+					property.Items["$ref"] = "#/definitions/" + field.Ref
+				}
 
 			} else if field.Type == "#object" {
+				property.Type = ""
 				property.Ref = "#/definitions/" + field.Ref
 			}
 
+			setPropertyOptions(&property, field, model)
+
 			definition.Properties[field.Name] = property
 		}
 
+		if len(model.Example) > 0 {
+			if err := json.Unmarshal([]byte(model.Example), &definition.Example); err != nil {
+				log.Printf("Model Error: @example of model %s is not valid JSON: %s (File: %s; Line: %d)", model.Name, err.Error(), model.FilePath, model.LineNum)
+			}
+		}
+
 		s.Swagger.Definitions[model.Name] = definition
 	}
 
+	applyParams, applyResponses := s.addReusables(allReusables)
+
 	s.Swagger.Paths = map[string]map[string]Path{}
 
 	for pathName, routes := range allRoutes {
 		s.Swagger.Paths[pathName] = map[string]Path{}
 		for _, route := range routes {
+			if len(route.Verb) == 0 {
+				log.Printf("Route Error: @route %s has no verb (File: %s; Line: %d)", route.OperationID, route.FilePath, route.LineNum)
+				continue
+			}
+			if s.ExcludeDeprecated && route.Deprecation.Deprecated {
+				continue
+			}
 			path := Path{}
 			// path.Description = route.Comments[0]
 			// path.OperationID = route.Comments[0]
-			path.Description = route.Description
+			path.Description = s.loadDescription(route.Description, route.FilePath, route.LineNum)
+			path.Summary = route.Summary
+			if len(path.Summary) == 0 {
+				path.Summary = Summarize(path.Description)
+			}
+			path.Deprecated = route.Deprecation.Deprecated
+			path.XSunset = route.Deprecation.Sunset
+			path.Extensions = route.Extensions
+			if note := route.Deprecation.Note(); len(note) > 0 {
+				path.Description = strings.TrimSpace(path.Description + "\n\n" + note)
+			}
 			path.OperationID = route.OperationID
-			path.Consumes = []string{"application/json"}
-			path.Produces = []string{"application/json"}
+			path.Consumes = operationMimeTypes(routeConsumes(route, allModels), s.Swagger.Consumes)
+			path.Produces = operationMimeTypes(route.Produces, s.Swagger.Produces)
 			path.Parameters = []Parameter{}
 			if len(route.Tags) > 0 {
 				path.Tags = route.Tags
 			}
+			if route.Security != nil {
+				security := route.Security
+				path.Security = &security
+				for _, err := range ValidateSecurity(route.Security, s.Swagger.SecurityDefinitions) {
+					log.Printf("Security Error: %s (File: %s; Line: %d)", err.Error(), route.FilePath, route.LineNum)
+				}
+			}
 			for _, param := range route.Params {
 
-				parameter := Parameter{}
-				paramType := param.Type
-
-				parameter.In = param.In
-				parameter.Name = param.Name
-				parameter.Description = param.Description
+				// Reference to a reusable parameter (@param $RequestID)
+				if len(param.Ref) > 0 {
+					if _, ok := s.Swagger.Parameters[param.Ref]; !ok {
+						log.Printf("Route Error: @param $%s references a parameter that is not defined (File: %s; Line: %d)", param.Ref, route.FilePath, route.LineNum)
+						continue
+					}
+					path.Parameters = append(path.Parameters, Parameter{Ref: "#/parameters/" + param.Ref})
+					continue
+				}
 
-				// Check if the return type is a known model
-				if _, ok := s.Swagger.Definitions[paramType]; ok {
-					parameter.Schema = map[string]string{}
-					parameter.Schema["$ref"] = "#/definitions/" + paramType
-				} else {
-					parameter.Required = param.Required
-					parameter.Schema = map[string]string{}
-					parameter.Type = paramType
+				// Swagger only allows model schemas in the body, so other models are expanded into a param per field
+				if model, ok := allModels[param.Type]; ok && param.In != TransportBody {
+					path.Parameters = append(path.Parameters, expandModelParams(param, model, allModels)...)
+					continue
 				}
-				path.Parameters = append(path.Parameters, parameter)
 
+				path.Parameters = append(path.Parameters, s.buildParameter(param))
+			}
+
+			// Reusable parameters applied to every operation
+			for _, name := range applyParams {
+				if !hasParamRef(route.Params, name) {
+					path.Parameters = append(path.Parameters, Parameter{Ref: "#/parameters/" + name})
+				}
 			}
 
 			// Responses
 			path.Responses = map[string]PathResponse{}
 			for _, response := range route.Responses {
-				pr := PathResponse{}
-				pr.Description = response.Description
-
-				if len(response.SchemaRef) > 0 && response.SchemaRef != "empty" {
-					pr.Schema = PathSchema{}
-					if response.SchemaRef[0:2] == "[]" {
-						pr.Schema.Type = "array"
-						pr.Schema.Items = map[string]string{}
-						pr.Schema.Items["$ref"] = "#/definitions/" + response.SchemaRef[2:]
-					} else {
-						pr.Schema.Ref = "#/definitions/" + response.SchemaRef
+
+				// Reference to a reusable response (@return 401 $Unauthorized)
+				if len(response.Ref) > 0 {
+					if _, ok := s.Swagger.Responses[response.Ref]; !ok {
+						log.Printf("Route Error: @return %s $%s references a response that is not defined (File: %s; Line: %d)", response.Key(), response.Ref, route.FilePath, route.LineNum)
+						continue
 					}
+					path.Responses[response.Key()] = PathResponse{Ref: "#/responses/" + response.Ref}
+					continue
 				}
 
-				path.Responses[strconv.Itoa(response.ResponseCode)] = pr
+				path.Responses[response.Key()] = buildResponse(response)
 			}
+
+			// Reusable responses applied to every operation
+			for responseKey, name := range applyResponses {
+				if _, ok := path.Responses[responseKey]; !ok {
+					path.Responses[responseKey] = PathResponse{Ref: "#/responses/" + name}
+				}
+			}
+
+			for _, header := range route.Headers {
+				pr, ok := path.Responses[header.ResponseKey]
+				if !ok {
+					log.Printf("Route Error: @header %s references response %s which is not declared by @route %s (File: %s; Line: %d)", header.Name, header.ResponseKey, route.OperationID, route.FilePath, route.LineNum)
+					continue
+				}
+				if len(pr.Ref) > 0 {
+					log.Printf("Route Error: @header %s cannot be added to the reusable response %s (File: %s; Line: %d)", header.Name, pr.Ref, route.FilePath, route.LineNum)
+					continue
+				}
+				if pr.Headers == nil {
+					pr.Headers = map[string]Header{}
+				}
+				responseHeader := Header{
+					Type:        header.Type,
+					Description: header.Description,
+				}
+				if len(header.ItemsType) > 0 {
+					responseHeader.Items = &ParameterItems{Type: header.ItemsType}
+				}
+				pr.Headers[header.Name] = responseHeader
+				path.Responses[header.ResponseKey] = pr
+			}
+			s.addExamples(route, &path)
+
 			s.Swagger.Paths[pathName][strings.ToLower(route.Verb)] = path
 		}
+
+		// Every route of the path was left out
+		if len(s.Swagger.Paths[pathName]) == 0 {
+			delete(s.Swagger.Paths, pathName)
+		}
+	}
+
+	if s.GenerateExamples {
+		s.generateExamples()
+	}
+
+	usedTags := []string{}
+	for _, path := range s.Swagger.Paths {
+		for _, operation := range path {
+			usedTags = append(usedTags, operation.Tags...)
+		}
+	}
+
+	tagDefs := []Tag{}
+	for _, tagDef := range allTagDefs {
+		tag := tagDef.Tag
+		tag.Description = s.loadDescription(tag.Description, tagDef.FilePath, tagDef.LineNum)
+		tagDefs = append(tagDefs, tag)
+	}
+
+	s.Swagger.Tags = BuildTags(s.Swagger.Tags, tagDefs, usedTags, s.TagOrder)
+
+	return nil
+}
+
+// discoverRoutes resolves the routes in `allRoutes` against router registrations and re-indexes them by path
+// Routes are resolved in the order they are declared (see sortedRoutes)
+func discoverRoutes(allRoutes map[string][]Route, routerRoutes []RouterRoute) map[string][]Route {
+
+	discovered := map[string][]Route{}
+
+	for _, route := range ResolveRouterRoutes(sortedRoutes(allRoutes), routerRoutes) {
+		discovered[route.Path] = append(discovered[route.Path], route)
+	}
+
+	return discovered
+}
+
+// uniqueRoutes keeps one route per verb and path in `allRoutes`, whether they are declared by @route or discovered
+// When several routes have the same verb and path, the first one declared is kept and the others are reported
+func uniqueRoutes(allRoutes map[string][]Route) map[string][]Route {
+
+	unique := map[string][]Route{}
+	declared := map[string]Route{}
+
+	for _, route := range sortedRoutes(allRoutes) {
+
+		if len(route.Verb) > 0 && len(route.Path) > 0 {
+			key := route.Verb + " " + route.Path
+			if first, ok := declared[key]; ok {
+				log.Printf("Route Error: @route %s resolves to %s, which is already declared by @route %s (File: %s; Line: %d)", route.OperationID, key, first.OperationID, route.FilePath, route.LineNum)
+				continue
+			}
+			declared[key] = route
+		}
+
+		unique[route.Path] = append(unique[route.Path], route)
+	}
+
+	return unique
+}
+
+// sortedRoutes returns the routes of `allRoutes` in the order they are declared (by file, line and name)
+func sortedRoutes(allRoutes map[string][]Route) (routes []Route) {
+
+	for _, pathRoutes := range allRoutes {
+		routes = append(routes, pathRoutes...)
 	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].FilePath != routes[j].FilePath {
+			return routes[i].FilePath < routes[j].FilePath
+		}
+		if routes[i].LineNum != routes[j].LineNum {
+			return routes[i].LineNum < routes[j].LineNum
+		}
+		return routes[i].OperationID < routes[j].OperationID
+	})
+
+	return
+}
+
+// operationMimeTypes returns the mime types an operation should declare
+// Operations inherit the global defaults from swagger-meta.json, so they only declare mime types that differ from them.
+// Without global defaults, operations fall back to `application/json`.
+func operationMimeTypes(routeMimeTypes []string, globalMimeTypes []string) []string {
+
+	if len(routeMimeTypes) == 0 {
+		if len(globalMimeTypes) > 0 {
+			return nil
+		}
+		return []string{MimeTypeJSON}
+	}
+
+	if len(routeMimeTypes) == len(globalMimeTypes) {
+		same := true
+		for _, mimeType := range routeMimeTypes {
+			if !inArray(mimeType, globalMimeTypes) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return nil
+		}
+	}
+
+	return routeMimeTypes
+}
+
+// ValidateSecurity checks that every scheme referenced by `requirements` exists in `definitions`,
+// along with the scopes of oauth2 schemes
+func ValidateSecurity(requirements []SecurityRequirement, definitions map[string]interface{}) (errs []error) {
+
+	for _, requirement := range requirements {
+		for name, scopes := range requirement {
+
+			definition, ok := definitions[name]
+			if !ok {
+				errs = append(errs, fmt.Errorf("Security scheme '%s' is not defined in securityDefinitions", name))
+				continue
+			}
+
+			definitionMap, ok := definition.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			definedScopes, ok := definitionMap["scopes"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, scope := range scopes {
+				if _, ok := definedScopes[scope]; !ok {
+					errs = append(errs, fmt.Errorf("Scope '%s' is not defined for security scheme '%s'", scope, name))
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// setParameterOptions copies the options of a non-body @param (enum, default, bounds etc.) to `parameter`
+func setParameterOptions(parameter *Parameter, param Param) {
+
+	parameter.Format = param.Format
+	parameter.Pattern = param.Pattern
+	parameter.CollectionFormat = param.CollectionFormat
+
+	valueType := param.Type
+	enum := []interface{}{}
+	if param.Type == SwaggerTypeArray {
+		valueType = param.ItemsType
+	}
+
+	for _, value := range param.Enum {
+		typed, _ := typedValue(valueType, value)
+		enum = append(enum, typed)
+	}
+
+	if len(param.Default) > 0 {
+		parameter.Default, _ = typedDefault(param)
+	}
+
+	switch param.Type {
+	case SwaggerTypeArray:
+		parameter.Items = &ParameterItems{
+			Type: param.ItemsType,
+		}
+		if len(enum) > 0 {
+			parameter.Items.Enum = enum
+		}
+		parameter.MinItems = intBound(param.Minimum)
+		parameter.MaxItems = intBound(param.Maximum)
+	case SwaggerTypeString:
+		parameter.MinLength = intBound(param.Minimum)
+		parameter.MaxLength = intBound(param.Maximum)
+	default:
+		parameter.Minimum = param.Minimum
+		parameter.Maximum = param.Maximum
+	}
+
+	if len(enum) > 0 && parameter.Items == nil {
+		parameter.Enum = enum
+	}
+}
+
+// intBound converts a parsed min:/max: bound to a length or item count
+func intBound(bound *float64) *int {
+	if bound == nil {
+		return nil
+	}
+	i := int(*bound)
+	return &i
+}
+
+// expandModelParams expands the fields of a query struct model (e.g. `@param query ListUsersQuery in:query`)
+// into a separate parameter per field, named after their `form`, `query`, `schema` or `json` struct tags
+// The fields promoted by embedded structs are expanded as well, if the embedded struct is one of `allModels`
+func expandModelParams(param Param, model Model, allModels map[string]Model) (parameters []Parameter) {
+	return expandModelFields(param, model, allModels, map[string]bool{model.Name: true})
+}
+
+// expandModelFields expands the fields of `model` (see expandModelParams), skipping the embedded structs in `expanded`
+func expandModelFields(param Param, model Model, allModels map[string]Model, expanded map[string]bool) (parameters []Parameter) {
+
+	for _, field := range model.Fields {
+
+		if field.Embedded {
+			embedded, ok := allModels[field.Ref]
+			if !ok {
+				log.Printf("Param Error: embedded struct %s of model %s is not a model, so its fields cannot be expanded into %s params (File: %s; Line: %d)", field.Ref, model.Name, param.In, model.FilePath, model.LineNum)
+				continue
+			}
+			if !expanded[field.Ref] {
+				expanded[field.Ref] = true
+				parameters = append(parameters, expandModelFields(param, embedded, allModels, expanded)...)
+			}
+			continue
+		}
+
+		name := field.QueryName()
+		if name == "-" {
+			continue
+		}
+
+		// Swagger 2.0 only uploads a single file per param, as form data
+		isFile := field.Type == SwaggerTypeFile
+
+		if field.Type == "#object" || (field.Type == "array" && len(field.ItemsType) == 0) || (isFile && param.In != TransportFormData) || field.ItemsType == SwaggerTypeFile {
+			log.Printf("Param Error: field %s of model %s cannot be expanded into a %s param (File: %s; Line: %d)", field.Name, model.Name, param.In, model.FilePath, model.LineNum)
+			continue
+		}
+
+		parameter := Parameter{}
+		parameter.In = param.In
+		parameter.Name = name
+		parameter.Description = field.Description
+		parameter.Required = field.Required || param.In == TransportPath
+		parameter.Type = field.Type
+
+		if field.Type == "array" {
+			parameter.Items = &ParameterItems{
+				Type: field.ItemsType,
+			}
+		}
+
+		parameters = append(parameters, parameter)
+	}
+
+	return
+}
+
+// routeConsumes returns the mime types a route consumes, adding `multipart/form-data` for routes that upload files
+func routeConsumes(route Route, allModels map[string]Model) []string {
+
+	for _, param := range route.Params {
+
+		hasFile := param.Type == SwaggerTypeFile
+
+		if model, ok := allModels[param.Type]; ok && param.In == TransportFormData {
+			hasFile = hasFileField(model, allModels, map[string]bool{model.Name: true})
+		}
+
+		if hasFile && !inArray(MimeTypeMultipart, route.Consumes) {
+			return append([]string{MimeTypeMultipart}, route.Consumes...)
+		}
+	}
+
+	return route.Consumes
+}
+
+// hasFileField checks if `model`, or one of the embedded structs that are not in `checked`, has a file field
+func hasFileField(model Model, allModels map[string]Model, checked map[string]bool) bool {
+
+	for _, field := range model.Fields {
+
+		if field.Type == SwaggerTypeFile || field.ItemsType == SwaggerTypeFile {
+			return true
+		}
+
+		if embedded, ok := allModels[field.Ref]; ok && field.Embedded && !checked[field.Ref] {
+			checked[field.Ref] = true
+			if hasFileField(embedded, allModels, checked) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// buildParameter builds a swagger parameter from a @param tag
+func (s *Swaggerf) buildParameter(param Param) Parameter {
+
+	parameter := Parameter{}
+	paramType := param.Type
+
+	parameter.In = param.In
+	parameter.Name = param.Name
+	parameter.Description = param.Description
+
+	// Check if the return type is a known model
+	if _, ok := s.Swagger.Definitions[paramType]; ok {
+		parameter.Schema = map[string]string{}
+		parameter.Schema["$ref"] = "#/definitions/" + paramType
+	} else {
+		parameter.Required = param.Required
+		parameter.Schema = map[string]string{}
+		parameter.Type = paramType
+		setParameterOptions(&parameter, param)
+	}
+
+	return parameter
+}
+
+// buildResponse builds a swagger response from a @return tag
+func buildResponse(response Response) PathResponse {
+
+	pr := PathResponse{}
+	pr.Description = response.Description
+
+	if len(response.SchemaRef) > 0 && response.SchemaRef != "empty" {
+		pr.Schema = PathSchema{}
+		if response.SchemaRef[0:2] == "[]" {
+			pr.Schema.Type = "array"
+			pr.Schema.Items = map[string]string{}
+			pr.Schema.Items["$ref"] = "#/definitions/" + response.SchemaRef[2:]
+		} else {
+			pr.Schema.Ref = "#/definitions/" + response.SchemaRef
+		}
+	}
+
+	return pr
+}
+
+// addReusables adds the reusable parameters (@paramdef) and responses (@responsedef) found in the source
+// to the ones defined in swagger-meta.json
+// Returns the parameters and responses of ApplyParams and ApplyResponses that are defined, leaving them unchanged
+// so every build applies the same ones
+func (s *Swaggerf) addReusables(reusables Reusables) (applyParams []string, applyResponses map[string]string) {
+
+	if s.Swagger.Parameters == nil && len(reusables.Params) > 0 {
+		s.Swagger.Parameters = map[string]Parameter{}
+	}
+
+	for name, param := range reusables.Params {
+		if _, ok := s.Swagger.Parameters[name]; ok {
+			log.Printf("Param Error: @paramdef %s is already defined in swagger-meta.json", name)
+			continue
+		}
+		s.Swagger.Parameters[name] = s.buildParameter(param)
+	}
+
+	if s.Swagger.Responses == nil && len(reusables.Responses) > 0 {
+		s.Swagger.Responses = map[string]PathResponse{}
+	}
+
+	for name, response := range reusables.Responses {
+		if _, ok := s.Swagger.Responses[name]; ok {
+			log.Printf("Response Error: @responsedef %s is already defined in swagger-meta.json", name)
+			continue
+		}
+		s.Swagger.Responses[name] = buildResponse(response)
+	}
+
+	applyParams = []string{}
+	for _, name := range s.ApplyParams {
+		if _, ok := s.Swagger.Parameters[name]; !ok {
+			log.Printf("Param Error: parameter %s cannot be applied to every operation because it is not defined", name)
+			continue
+		}
+		applyParams = append(applyParams, name)
+	}
+
+	applyResponses = map[string]string{}
+	for responseKey, name := range s.ApplyResponses {
+		if _, ok := s.Swagger.Responses[name]; !ok {
+			log.Printf("Response Error: response %s cannot be applied to every operation because it is not defined", name)
+			continue
+		}
+		applyResponses[responseKey] = name
+	}
+
+	return
+}
+
+// hasParamRef checks if `params` reference the reusable parameter `name`
+func hasParamRef(params []Param, name string) bool {
+	for _, param := range params {
+		if param.Ref == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseApplyResponses parses a comma separated list of `code=name` pairs of reusable responses
+// Example: 401=Unauthorized,default=ServerError
+func ParseApplyResponses(value string) (applyResponses map[string]string, err error) {
+
+	applyResponses = map[string]string{}
+
+	for _, pair := range strings.Split(value, ",") {
+
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+
+		pairParts := strings.Split(pair, "=")
+
+		if len(pairParts) != 2 {
+			err = fmt.Errorf("Invalid response '%s' (expected code=name)", pair)
+			return
+		}
+
+		responseKey := strings.TrimSpace(pairParts[0])
+
+		if _, _, err = ParseResponseKey(responseKey); err != nil {
+			return
+		}
+
+		applyResponses[responseKey] = strings.TrimPrefix(strings.TrimSpace(pairParts[1]), "$")
+	}
+
+	return
+}
+
+// setPropertyOptions applies the tags in a field's comments (@format, @enum, @min, @max, @example, @x-...) to its property
+func setPropertyOptions(property *Property, field ModelField, model Model) {
+
+	valueType := property.Type
+	if valueType == SwaggerTypeArray {
+		valueType = property.Items["type"]
+	}
+
+	if formats, ok := field.Tags[TagFormat]; ok {
+		property.Format = formats[0]
+	}
+
+	if enums, ok := field.Tags[TagEnum]; ok {
+		for _, value := range strings.Split(enums[0], "|") {
+			typed, err := typedValue(valueType, strings.TrimSpace(value))
+			if err != nil {
+				log.Printf("Model Error: @enum of field %s of model %s: %s (File: %s; Line: %d)", field.Name, model.Name, err.Error(), model.FilePath, model.LineNum)
+				continue
+			}
+			property.Enum = append(property.Enum, typed)
+		}
+	}
+
+	for _, tagName := range []string{TagMin, TagMax} {
+		values, ok := field.Tags[tagName]
+		if !ok {
+			continue
+		}
+		bound, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			log.Printf("Model Error: invalid @%s '%s' of field %s of model %s (File: %s; Line: %d)", tagName, values[0], field.Name, model.Name, model.FilePath, model.LineNum)
+			continue
+		}
+		if tagName == TagMin {
+			property.Minimum = &bound
+		} else {
+			property.Maximum = &bound
+		}
+	}
+
+	if examples, ok := field.Tags[TagExample]; ok {
+		property.Example = exampleValue(property.Type, examples[0])
+	}
+
+	extensions, err := ParseExtensions(field.Tags)
+	if err != nil {
+		log.Printf("Model Error: field %s of model %s: %s (File: %s; Line: %d)", field.Name, model.Name, err.Error(), model.FilePath, model.LineNum)
+	}
+	property.Extensions = extensions
+}
+
+// exampleValue converts a field's @example hint to the type of the field
+// Objects and arrays are given as JSON, anything that cannot be converted is used as a string
+func exampleValue(swaggerType string, value string) (example interface{}) {
+
+	if swaggerType == SwaggerTypeArray || len(swaggerType) == 0 {
+		if err := json.Unmarshal([]byte(value), &example); err == nil {
+			return
+		}
+	}
+
+	example, err := typedValue(swaggerType, value)
+
+	if err != nil {
+		example = value
+	}
+
+	return
 }
@@ -1,7 +1,291 @@
 package main
 
-import "testing"
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
 
 func TestBuildSwagger(t *testing.T) {
-    
-}
\ No newline at end of file
+
+}
+
+func TestOperationMimeTypes(t *testing.T) {
+
+	if mimeTypes := operationMimeTypes(nil, nil); len(mimeTypes) != 1 || mimeTypes[0] != MimeTypeJSON {
+		t.Errorf("operationMimeTypes should have defaulted to [%s] (actually %v)", MimeTypeJSON, mimeTypes)
+	}
+
+	if mimeTypes := operationMimeTypes(nil, []string{"text/csv"}); mimeTypes != nil {
+		t.Errorf("operationMimeTypes should have inherited the global mime types (actually %v)", mimeTypes)
+	}
+
+	if mimeTypes := operationMimeTypes([]string{"text/csv"}, []string{"text/csv"}); mimeTypes != nil {
+		t.Errorf("operationMimeTypes should have omitted mime types equal to the global mime types (actually %v)", mimeTypes)
+	}
+
+	if mimeTypes := operationMimeTypes([]string{"text/csv"}, []string{MimeTypeJSON}); len(mimeTypes) != 1 || mimeTypes[0] != "text/csv" {
+		t.Errorf("operationMimeTypes should have returned [text/csv] (actually %v)", mimeTypes)
+	}
+}
+
+func TestValidateSecurity(t *testing.T) {
+
+	definitions := map[string]interface{}{
+		"bearerAuth": map[string]interface{}{
+			"type": "apiKey",
+		},
+		"oauth2": map[string]interface{}{
+			"type": "oauth2",
+			"scopes": map[string]interface{}{
+				"read:users": "Read users",
+			},
+		},
+	}
+
+	requirements := []SecurityRequirement{
+		{"bearerAuth": []string{}},
+		{"oauth2": []string{"read:users", "write:users"}},
+		{"basicAuth": []string{}},
+	}
+
+	errs := ValidateSecurity(requirements, definitions)
+
+	if len(errs) != 2 {
+		t.Errorf("ValidateSecurity should have returned %d errors (actually %d)", 2, len(errs))
+	}
+}
+
+func TestSetParameterOptions(t *testing.T) {
+
+	param, _ := ParseRouteParam("status []string collectionFormat:pipes enum:a|b max:3 Filter by status")
+	parameter := Parameter{}
+	setParameterOptions(&parameter, param)
+
+	if parameter.Items == nil || parameter.Items.Type != "string" || len(parameter.Items.Enum) != 2 {
+		t.Errorf("setParameterOptions should have set string items with an enum of 2 values (actually %v)", parameter.Items)
+	}
+
+	if parameter.Enum != nil {
+		t.Errorf("setParameterOptions should have set the enum on the items of an array (actually %v)", parameter.Enum)
+	}
+
+	if parameter.MaxItems == nil || *parameter.MaxItems != 3 {
+		t.Errorf("setParameterOptions should have set MaxItems == 3")
+	}
+
+	param, _ = ParseRouteParam("limit int default:20 min:1 The number of results")
+	parameter = Parameter{}
+	setParameterOptions(&parameter, param)
+
+	if parameter.Default != int64(20) {
+		t.Errorf("setParameterOptions should have set an integer default of 20 (actually %v)", parameter.Default)
+	}
+
+	if parameter.Minimum == nil || *parameter.Minimum != 1 {
+		t.Errorf("setParameterOptions should have set Minimum == 1")
+	}
+
+	param, _ = ParseRouteParam("ids []int collectionFormat:pipes default:1|2 The ids")
+	parameter = Parameter{}
+	setParameterOptions(&parameter, param)
+
+	if defaults, ok := parameter.Default.([]interface{}); !ok || len(defaults) != 2 || defaults[0] != int64(1) || defaults[1] != int64(2) {
+		t.Errorf("setParameterOptions should have set an array default of [1 2] (actually %v)", parameter.Default)
+	}
+}
+
+func TestExpandModelParams(t *testing.T) {
+
+	model := Model{
+		Name: "ListUsersQuery",
+		Fields: []ModelField{
+			{Name: "Page", Type: "integer", Required: true, Description: "The page number", StructTag: `form:"page"`},
+			{Name: "Status", Type: "array", ItemsType: "string", StructTag: `form:"status"`},
+			{Name: "Internal", Type: "string", StructTag: `form:"-"`},
+			{Name: "Owner", Type: "#object", Ref: "User"},
+		},
+	}
+
+	form := Model{
+		Name: "UploadForm",
+		Fields: []ModelField{
+			{Name: "Files", Type: "array", ItemsType: "file", StructTag: `form:"files"`},
+		},
+	}
+
+	if parameters := expandModelParams(Param{Name: "form", Type: "UploadForm", In: "formData"}, form, map[string]Model{}); len(parameters) != 0 {
+		t.Errorf("expandModelParams should not have expanded an array of files (actually %v)", parameters)
+	}
+
+	parameters := expandModelParams(Param{Name: "query", Type: "ListUsersQuery", In: "query"}, model, map[string]Model{})
+
+	if len(parameters) != 2 {
+		t.Fatalf("expandModelParams should have returned %d parameters (actually %d)", 2, len(parameters))
+	}
+
+	if parameters[0].Name != "page" || parameters[0].In != "query" || parameters[0].Type != "integer" || !parameters[0].Required {
+		t.Errorf("expandModelParams should have returned a required integer query param named page (actually %v)", parameters[0])
+	}
+
+	if parameters[1].Name != "status" || parameters[1].Items == nil || parameters[1].Items.Type != "string" {
+		t.Errorf("expandModelParams should have returned an array of string query param named status (actually %v)", parameters[1])
+	}
+}
+
+func TestExpandModelParams_Embedded(t *testing.T) {
+
+	models := map[string]Model{
+		"Pagination": {
+			Name: "Pagination",
+			Fields: []ModelField{
+				{Name: "Page", Type: "integer", StructTag: `form:"page"`},
+			},
+		},
+	}
+
+	model := Model{
+		Name: "GetUserParams",
+		Fields: []ModelField{
+			{Name: "Pagination", Type: "#object", Ref: "Pagination", Embedded: true},
+			{Name: "Unknown", Type: "#object", Ref: "Unknown", Embedded: true},
+			{Name: "ID", Type: "integer", StructTag: `uri:"id" form:"id"`},
+		},
+	}
+
+	parameters := expandModelParams(Param{Name: "params", Type: "GetUserParams", In: "path"}, model, models)
+
+	if len(parameters) != 2 {
+		t.Fatalf("expandModelParams should have returned %d parameters (actually %d)", 2, len(parameters))
+	}
+
+	if parameters[0].Name != "page" {
+		t.Errorf("expandModelParams should have expanded the fields of the embedded Pagination struct (actually %v)", parameters[0])
+	}
+
+	for _, parameter := range parameters {
+		if !parameter.Required {
+			t.Errorf("expandModelParams should have returned required path params (actually %v)", parameter)
+		}
+	}
+}
+
+func TestRouteConsumes(t *testing.T) {
+
+	route := Route{
+		Params: []Param{
+			{Name: "upload", Type: "file", In: "formData"},
+		},
+	}
+
+	if consumes := routeConsumes(route, map[string]Model{}); len(consumes) != 1 || consumes[0] != MimeTypeMultipart {
+		t.Errorf("routeConsumes should have returned [%s] (actually %v)", MimeTypeMultipart, consumes)
+	}
+
+	route = Route{
+		Params: []Param{
+			{Name: "form", Type: "UploadForm", In: "formData"},
+		},
+		Consumes: []string{MimeTypeMultipart},
+	}
+
+	models := map[string]Model{
+		"UploadForm": {Fields: []ModelField{{Name: "File", Type: "file"}}},
+	}
+
+	if consumes := routeConsumes(route, models); len(consumes) != 1 {
+		t.Errorf("routeConsumes should not have added %s twice (actually %v)", MimeTypeMultipart, consumes)
+	}
+}
+
+func TestAddReusables(t *testing.T) {
+
+	s := Swaggerf{
+		ApplyParams:    []string{"RequestID", "Missing"},
+		ApplyResponses: map[string]string{"401": "Unauthorized", "500": "Missing"},
+	}
+
+	reusables := Reusables{
+		Params:    map[string]Param{"RequestID": {Name: "X-Request-ID", Type: "string", In: "header"}},
+		Responses: map[string]Response{"Unauthorized": {ResponseCode: 401}},
+	}
+
+	applyParams, applyResponses := s.addReusables(reusables)
+
+	if len(applyParams) != 1 || applyParams[0] != "RequestID" {
+		t.Errorf("addReusables should have returned the RequestID parameter (actually %v)", applyParams)
+	}
+
+	if len(applyResponses) != 1 || applyResponses["401"] != "Unauthorized" {
+		t.Errorf("addReusables should have returned the Unauthorized response (actually %v)", applyResponses)
+	}
+
+	if len(s.ApplyParams) != 2 || len(s.ApplyResponses) != 2 {
+		t.Errorf("addReusables should have left ApplyParams and ApplyResponses unchanged (actually %v and %v)", s.ApplyParams, s.ApplyResponses)
+	}
+}
+
+func TestParseApplyResponses(t *testing.T) {
+
+	applyResponses, err := ParseApplyResponses("401=Unauthorized, default=$ServerError")
+
+	if err != nil {
+		t.Errorf("ParseApplyResponses should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if applyResponses["401"] != "Unauthorized" || applyResponses["default"] != "ServerError" {
+		t.Errorf("ParseApplyResponses returned unexpected responses (%v)", applyResponses)
+	}
+
+	if _, err := ParseApplyResponses("Unauthorized"); err == nil {
+		t.Errorf("ParseApplyResponses should have returned an error (actually nil)")
+	}
+}
+
+func TestDiscoverRoutes(t *testing.T) {
+
+	allRoutes := map[string][]Route{
+		"":       {{OperationID: "GetUser", FilePath: "api/b.go", LineNum: 3}},
+		"/users": {{OperationID: "ListUsers", Verb: "GET", Path: "/users", FilePath: "api/c.go", LineNum: 1}},
+		"/user":  {{OperationID: "FindUser", Verb: "GET", Path: "/user", FilePath: "api/a.go", LineNum: 8}},
+	}
+	routerRoutes := []RouterRoute{{Verb: "GET", Path: "/user", Handlers: []string{"GetUser"}}}
+
+	// The route declared first is kept, whatever the order of the map
+	for i := 0; i < 10; i++ {
+		discovered := uniqueRoutes(discoverRoutes(allRoutes, routerRoutes))
+		if routes := discovered["/user"]; len(routes) != 1 || routes[0].OperationID != "FindUser" {
+			t.Fatalf("discoverRoutes should have kept only FindUser for GET /user (actually %v)", routes)
+		}
+		if routes := discovered["/users"]; len(routes) != 1 || routes[0].OperationID != "ListUsers" {
+			t.Fatalf("discoverRoutes should have kept ListUsers for GET /users (actually %v)", routes)
+		}
+	}
+}
+
+func TestUniqueRoutes(t *testing.T) {
+
+	logs := bytes.Buffer{}
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	allRoutes := map[string][]Route{
+		"/users": {
+			{OperationID: "SearchUsers", Verb: "GET", Path: "/users", FilePath: "api/b.go", LineNum: 2},
+			{OperationID: "CreateUser", Verb: "POST", Path: "/users", FilePath: "api/b.go", LineNum: 9},
+			{OperationID: "ListUsers", Verb: "GET", Path: "/users", FilePath: "api/a.go", LineNum: 5},
+		},
+	}
+
+	unique := uniqueRoutes(allRoutes)
+
+	if routes := unique["/users"]; len(routes) != 2 || routes[0].OperationID != "ListUsers" || routes[1].OperationID != "CreateUser" {
+		t.Errorf("uniqueRoutes should have kept ListUsers and CreateUser for /users (actually %v)", routes)
+	}
+
+	if !strings.Contains(logs.String(), "@route SearchUsers resolves to GET /users, which is already declared by @route ListUsers") {
+		t.Errorf("uniqueRoutes should have reported SearchUsers (actually %s)", logs.String())
+	}
+}
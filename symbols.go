@@ -2,35 +2,103 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 )
 
 // Tag Constants
 const (
-	TagDescription        = "description"
-	TagRoute              = "route"
-	TagModel              = "model"
-	TagReturn             = "return"
-	TagParam              = "param"
-	TagTags               = "tags"
-	TagArgRequired        = "required"
-	TagArgOptional        = "optional"
-	TagArgTransportPrefix = "in:"
-	GoTypeInt             = "int"
-	SwaggerTypeInt        = "integer"
-	GoTypeString          = "string"
-	SwaggerTypeString     = "string"
-	GoTypeFloat           = "float"
-	SwaggerTypeFloat      = "number"
-	GoTypeBool            = "bool"
-	SwaggerTypeBool       = "boolean"
-	TransportPath         = "path"
-	TransportQuery        = "query"
-	TransportForm         = "form"
-	TransportHeader       = "header"
-	TransportBody         = "body"
+	TagDescription               = "description"
+	TagRoute                     = "route"
+	TagModel                     = "model"
+	TagReturn                    = "return"
+	TagParam                     = "param"
+	TagTags                      = "tags"
+	TagTag                       = "tag"
+	TagTagDef                    = "tagdef"
+	TagOrderDeclared             = "declared"
+	TagOrderAlpha                = "alpha"
+	TagConsumes                  = "consumes"
+	TagProduces                  = "produces"
+	TagSecurity                  = "security"
+	TagArgSecurityNone           = "none"
+	TagHeader                    = "header"
+	TagArgDefaultResponse        = "default"
+	TagDeprecated                = "deprecated"
+	TagSunset                    = "sunset"
+	TagQueryParams               = "queryparams"
+	TagParamDef                  = "paramdef"
+	TagResponseDef               = "responsedef"
+	ReusablePrefix               = "$"
+	TagArgFilePrefix             = "file:"
+	TagExample                   = "example"
+	TagArgExampleBody            = "body"
+	TagEnum                      = "enum"
+	TagMin                       = "min"
+	TagMax                       = "max"
+	TagFormat                    = "format"
+	VendorExtensionPrefix        = "x-"
+	SunsetDateFormat             = "2006-01-02"
+	TagArgRequired               = "required"
+	TagArgOptional               = "optional"
+	TagArgTransportPrefix        = "in:"
+	TagArgCollectionFormatPrefix = "collectionFormat:"
+	TagArgEnumPrefix             = "enum:"
+	TagArgDefaultPrefix          = "default:"
+	TagArgMinPrefix              = "min:"
+	TagArgMaxPrefix              = "max:"
+	TagArgPatternPrefix          = "pattern:"
+	TagArgFormatPrefix           = "format:"
+	GoTypeInt                    = "int"
+	SwaggerTypeInt               = "integer"
+	GoTypeString                 = "string"
+	SwaggerTypeString            = "string"
+	GoTypeFloat                  = "float"
+	SwaggerTypeFloat             = "number"
+	GoTypeBool                   = "bool"
+	SwaggerTypeBool              = "boolean"
+	SwaggerTypeArray             = "array"
+	SwaggerTypeFile              = "file"
+	TransportPath                = "path"
+	TransportQuery               = "query"
+	TransportForm                = "form"
+	TransportFormData            = "formData"
+	TransportHeader              = "header"
+	TransportBody                = "body"
+	MimeTypeJSON                 = "application/json"
+	MimeTypeMultipart            = "multipart/form-data"
+	MimeTypeFormURLEncoded       = "application/x-www-form-urlencoded"
 )
 
+// HeaderTypes is a collection of the types of response headers, which can also be arrays of these types
+var HeaderTypes = []string{
+	SwaggerTypeString,
+	SwaggerTypeInt,
+	SwaggerTypeFloat,
+	SwaggerTypeBool,
+}
+
+// CollectionFormats is a collection of the formats array params can be serialized with
+var CollectionFormats = []string{
+	"csv",
+	"ssv",
+	"tsv",
+	"pipes",
+	"multi",
+}
+
+// CollectionFormatSeparators are the separators of the values of an array param, by collection format
+// `multi` (a repeated param) has no separator, so its default values are separated by commas
+var CollectionFormatSeparators = map[string]string{
+	"":      ",",
+	"csv":   ",",
+	"ssv":   " ",
+	"tsv":   "\t",
+	"pipes": "|",
+	"multi": ",",
+}
+
 // Tags is a collection of tagName constants
 var Tags = []string{
 	TagDescription,
@@ -39,6 +107,38 @@ var Tags = []string{
 	TagReturn,
 	TagParam,
 	TagTags,
+	TagTag,
+	TagTagDef,
+	TagConsumes,
+	TagProduces,
+	TagSecurity,
+	TagHeader,
+	TagDeprecated,
+	TagSunset,
+	TagQueryParams,
+	TagParamDef,
+	TagResponseDef,
+	TagExample,
+	TagEnum,
+	TagMin,
+	TagMax,
+	TagFormat,
+}
+
+// SingleLineTags are the tags whose value is a single line. The lines following them are free text
+var SingleLineTags = []string{
+	TagModel,
+	TagTags,
+	TagTag,
+	TagConsumes,
+	TagProduces,
+	TagSecurity,
+	TagSunset,
+	TagQueryParams,
+	TagEnum,
+	TagMin,
+	TagMax,
+	TagFormat,
 }
 
 // GetSymbols returns a collection of symbol objects based on a symbol string
@@ -79,39 +179,75 @@ func GetSymbols(lines []string, symbol string) (symbols []Symbol, err error) {
 // GetCommentBlock parses an entire comment block (comments above a function or struct) and returns them as a string array,
 // along with the numeric start and end position of the block
 func GetCommentBlock(lines []string, startLine int) (comments []string, blockStart int, blockEnd int) {
+	return getCommentBlock(ExtractComments(lines), startLine)
+}
 
-	currentLine := startLine
+// getCommentBlock returns the text of the comment block around line `startLine` of a file's extracted comments
+func getCommentBlock(commentLines []CommentLine, startLine int) (comments []string, blockStart int, blockEnd int) {
 
-	// Go backwards
-	for {
-		currentLine = currentLine - 1
-		// TODO support docblock (`*`)
-		if currentLine < 1 || len(lines[currentLine]) < 3 || lines[currentLine][0:3] != "// " {
-			// Start of block
-			blockStart = currentLine + 1
-			break
-		}
+	blockStart = startLine
+	for blockStart > 0 && commentLines[blockStart-1].IsComment {
+		blockStart = blockStart - 1
+	}
 
-		// prepend
-		comments = append([]string{lines[currentLine][3:]}, comments...)
+	blockEnd = startLine
+	for blockEnd < len(commentLines)-1 && commentLines[blockEnd+1].IsComment {
+		blockEnd = blockEnd + 1
 	}
 
-	// Reset currentLine
-	currentLine = startLine
-	comments = append(comments, lines[currentLine][3:])
+	for _, commentLine := range commentLines[blockStart : blockEnd+1] {
+		if !commentLine.Skip {
+			comments = append(comments, commentLine.Text)
+		}
+	}
 
-	for {
-		currentLine = currentLine + 1
-		if len(lines[currentLine]) < 3 || lines[currentLine][0:3] != "// " {
-			blockEnd = currentLine - 1
-			break
+	return
+}
+
+// ParseDeprecation reads the @deprecated and @sunset tags of a comment block
+// Examples: @deprecated Use GetFoos instead, @sunset 2027-01-01
+func ParseDeprecation(tags map[string][]string) (deprecation Deprecation, err error) {
+
+	if reasons, ok := tags[TagDeprecated]; ok {
+		deprecation.Deprecated = true
+		deprecation.Reason = strings.Join(reasons, " ")
+	}
+
+	if sunsets, ok := tags[TagSunset]; ok && len(sunsets) > 0 {
+		if !deprecation.Deprecated {
+			err = fmt.Errorf("@sunset %s can only be used along with @deprecated", sunsets[0])
+			return
 		}
-		comments = append(comments, lines[currentLine][3:])
+		if _, err = time.Parse(SunsetDateFormat, sunsets[0]); err != nil {
+			err = fmt.Errorf("Invalid sunset date '%s' (expected YYYY-MM-DD)", sunsets[0])
+			return
+		}
+		deprecation.Sunset = sunsets[0]
 	}
 
 	return
 }
 
+// Note returns the note added to the description of a deprecated route or model
+func (d Deprecation) Note() string {
+
+	if !d.Deprecated {
+		return ""
+	}
+
+	note := "Deprecated"
+
+	if len(d.Reason) > 0 {
+		note = note + ": " + d.Reason
+	}
+
+	if len(d.Sunset) > 0 {
+		note = note + " (sunset " + d.Sunset + ")"
+	}
+
+	return note
+}
+
 func inArray(needle string, haystack []string) bool {
 	for _, el := range haystack {
 		if el == needle {
@@ -123,34 +259,135 @@ func inArray(needle string, haystack []string) bool {
 }
 
 // ParseSymbols looks for comment tags (starts with `@`) and returns what it finds as a multi-dimensional array
+// Lines following a tag continue its value (joined with newlines) until a blank line or the next tag.
+// Indented lines continue the value across blank lines. Tags in SingleLineTags are never continued.
 func ParseSymbols(lines []string) (tags map[string][]string) {
+	tags, _ = parseCommentBlock(lines)
+	return
+}
+
+// ParseDescription returns the free text of a comment block (every line that is not part of a tag) as markdown
+func ParseDescription(lines []string) string {
+	_, description := parseCommentBlock(lines)
+	return description
+}
+
+// Summarize returns the first sentence of a description
+func Summarize(description string) string {
+
+	summary := strings.TrimSpace(strings.SplitN(description, "\n\n", 2)[0])
+	summary = strings.Join(strings.Fields(summary), " ")
+
+	if sentenceEnd := strings.Index(summary, ". "); sentenceEnd > -1 {
+		summary = summary[0 : sentenceEnd+1]
+	}
+
+	return summary
+}
+
+// parseCommentBlock splits a comment block into its tags and its free text
+func parseCommentBlock(lines []string) (tags map[string][]string, description string) {
 
 	tags = map[string][]string{}
+	descriptionLines := []string{}
 
-	for _, line := range lines {
+	// The tag whose value is being continued
+	tagName := ""
+	tagLines := []string{}
+	blankLines := 0
 
-		if !strings.HasPrefix(line, "@") {
-			continue
+	endTag := func() {
+		if len(tagName) > 0 {
+			last := len(tags[tagName]) - 1
+			if continuation := dedent(tagLines); len(continuation) > 0 {
+				tags[tagName][last] = strings.TrimSpace(tags[tagName][last] + "\n" + continuation)
+			}
 		}
+		tagName = ""
+		tagLines = []string{}
+		blankLines = 0
+	}
 
-		lineParts := strings.Fields(line)
-		// Remove the `@` symbol
-		tagName := lineParts[0][1:]
+	for _, line := range lines {
+
+		trimmedLine := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmedLine, "@") {
+
+			endTag()
+
+			lineParts := strings.Fields(trimmedLine)
+			// Remove the `@` symbol
+			name := lineParts[0][1:]
 
-		if !inArray(tagName, Tags) {
+			if !inArray(name, Tags) && !IsVendorExtension(name) {
+				continue
+			}
+
+			tags[name] = append(tags[name], strings.Join(lineParts[1:], " "))
+			if !inArray(name, SingleLineTags) {
+				tagName = name
+			}
 			continue
 		}
 
-		if _, ok := tags[tagName]; !ok {
-			tags[tagName] = []string{}
+		if len(tagName) > 0 {
+
+			if len(trimmedLine) == 0 {
+				blankLines = blankLines + 1
+				continue
+			}
+
+			isIndented := strings.TrimLeft(line, " \t") != line
+
+			if isIndented || blankLines == 0 {
+				for ; blankLines > 0; blankLines-- {
+					tagLines = append(tagLines, "")
+				}
+				tagLines = append(tagLines, line)
+				continue
+			}
+
+			endTag()
 		}
 
-		tags[tagName] = append(tags[tagName], strings.Join(lineParts[1:], " "))
+		descriptionLines = append(descriptionLines, line)
 	}
 
+	endTag()
+
+	description = strings.TrimSpace(dedent(descriptionLines))
+
 	return
 }
 
+// dedent removes the indentation that all non-blank `lines` have in common and joins them with newlines
+func dedent(lines []string) string {
+
+	indent := -1
+
+	for _, line := range lines {
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || lineIndent < indent {
+			indent = lineIndent
+		}
+	}
+
+	dedented := make([]string, len(lines))
+
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		dedented[i] = strings.TrimRight(line, " \t")
+	}
+
+	return strings.Join(dedented, "\n")
+}
+
 // TODO scan entire package for files
 // TODO reference other models from within a model
 // TODO allow for models (@model) and routes (@route) to be identified by their tags -- allow them to live in the same file.
@@ -144,3 +144,133 @@ func mapShouldContainKey(testMap map[string][]string, key string, t *testing.T)
 		t.Errorf("Map should contain key `%s` but does not", key)
 	}
 }
+
+func TestParseDeprecation(t *testing.T) {
+
+	tags := map[string][]string{
+		"deprecated": {"Use GetFoos instead"},
+		"sunset":     {"2027-01-01"},
+	}
+
+	deprecation, err := ParseDeprecation(tags)
+
+	if err != nil {
+		t.Errorf("ParseDeprecation should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if !deprecation.Deprecated || deprecation.Reason != "Use GetFoos instead" || deprecation.Sunset != "2027-01-01" {
+		t.Errorf("ParseDeprecation returned an unexpected deprecation (%v)", deprecation)
+	}
+
+	note := "Deprecated: Use GetFoos instead (sunset 2027-01-01)"
+	if deprecation.Note() != note {
+		t.Errorf("Deprecation.Note should have returned '%s' (actually '%s')", note, deprecation.Note())
+	}
+}
+
+func TestParseDeprecation_ShouldReturnError(t *testing.T) {
+
+	_, err := ParseDeprecation(map[string][]string{
+		"deprecated": {""},
+		"sunset":     {"next year"},
+	})
+
+	if err == nil {
+		t.Errorf("ParseDeprecation should have returned an error (actually nil)")
+	}
+
+	deprecation, err := ParseDeprecation(map[string][]string{
+		"sunset": {"2027-01-01"},
+	})
+
+	if err == nil || len(deprecation.Sunset) > 0 {
+		t.Errorf("ParseDeprecation should have returned an error and no sunset for @sunset without @deprecated (actually %v, %v)", deprecation, err)
+	}
+}
+
+func TestParseSymbols_Continuation(t *testing.T) {
+
+	lines := []string{
+		"GetFoo returns a foo. It is cached.",
+		"",
+		"@route GetFoo GET /foo Returns a foo",
+		"with a description that",
+		"spans two lines",
+		"@description A long description",
+		"",
+		"  | Code | Meaning |",
+		"  | ---- | ------- |",
+		"",
+		"Trailing free text",
+	}
+
+	tagMap, description := parseCommentBlock(lines)
+
+	route := "GetFoo GET /foo Returns a foo\nwith a description that\nspans two lines"
+	if tagMap["route"][0] != route {
+		t.Errorf("ParseSymbols should have continued the route tag as '%s' (actually '%s')", route, tagMap["route"][0])
+	}
+
+	tagDescription := "A long description\n\n| Code | Meaning |\n| ---- | ------- |"
+	if tagMap["description"][0] != tagDescription {
+		t.Errorf("ParseSymbols should have continued the description tag with its indented lines as '%s' (actually '%s')", tagDescription, tagMap["description"][0])
+	}
+
+	freeText := "GetFoo returns a foo. It is cached.\n\nTrailing free text"
+	if description != freeText {
+		t.Errorf("ParseDescription should have returned '%s' (actually '%s')", freeText, description)
+	}
+}
+
+func TestParseSymbols_SingleLineTags(t *testing.T) {
+
+	lines := []string{
+		"@model User",
+		"A user of the system",
+		"@sunset 2027-01-01",
+		"@produces application/json",
+		"Users are returned as json",
+	}
+
+	tagMap, description := parseCommentBlock(lines)
+
+	if tagMap["model"][0] != "User" || tagMap["sunset"][0] != "2027-01-01" || tagMap["produces"][0] != "application/json" {
+		t.Errorf("ParseSymbols should not have continued single line tags (actually %v)", tagMap)
+	}
+
+	freeText := "A user of the system\nUsers are returned as json"
+	if description != freeText {
+		t.Errorf("ParseDescription should have returned '%s' (actually '%s')", freeText, description)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+
+	summary := Summarize("GetFoo returns a foo. It is cached\nfor a minute.\n\nSecond paragraph")
+
+	if summary != "GetFoo returns a foo." {
+		t.Errorf("Summarize should have returned '%s' (actually '%s')", "GetFoo returns a foo.", summary)
+	}
+
+	summary = Summarize("Returns a foo\nby its id")
+
+	if summary != "Returns a foo by its id" {
+		t.Errorf("Summarize should have returned '%s' (actually '%s')", "Returns a foo by its id", summary)
+	}
+}
+
+func TestGetCommentBlock_EmptyCommentLines(t *testing.T) {
+
+	lines := []string{
+		"// GetFoo returns a foo",
+		"//",
+		"// @route GetFoo GET /foo",
+		"func GetFoo() {",
+	}
+
+	comments, blockStart, blockEnd := GetCommentBlock(lines, 2)
+
+	if len(comments) != 3 || blockStart != 0 || blockEnd != 2 {
+		t.Errorf("GetCommentBlock should have returned 3 comments from line 0 to 2 (actually %d from line %d to %d)", len(comments), blockStart, blockEnd)
+	}
+}
@@ -0,0 +1,134 @@
+/**
+ * Tags
+ */
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// GetTagDefs searches `lines` for tag definitions (@tagdef)
+// Example: @tagdef users "User management" https://docs.example.com/users
+func GetTagDefs(lines []string, filePath string) (tagDefs []TagDef) {
+	return getTagDefs(filePath, ScanSymbols(lines)[TagTagDef], log.Printf)
+}
+
+// getTagDefs parses the tag definitions of the @tagdef `symbols`, reporting errors with `report`
+func getTagDefs(filePath string, symbols []Symbol, report reportFunc) (tagDefs []TagDef) {
+
+	for _, symbol := range symbols {
+
+		lineNum := symbol.LineNum
+
+		for _, ret := range ParseSymbols([]string{symbol.Line[strings.Index(symbol.Line, "@"):]})[TagTagDef] {
+			tag, err := ParseTagDef(ret)
+			if err != nil {
+				report("Tag Error: %s (File: %s; Line: %d)", err.Error(), filePath, lineNum)
+				continue
+			}
+			tagDefs = append(tagDefs, TagDef{tag, filePath, lineNum})
+		}
+	}
+
+	return
+}
+
+// ParseTagDef parses a tag definition (@tagdef)
+// The name is followed by an optional (quoted) description and an optional external docs url
+// Examples: @tagdef users "User management" https://docs.example.com/users, @tagdef orders Order management
+func ParseTagDef(ret string) (tag Tag, err error) {
+
+	retParts := strings.Fields(ret)
+
+	if len(retParts) == 0 {
+		err = fmt.Errorf("The tag @tagdef is not in the correct format: '%s'", ret)
+		return
+	}
+
+	tag.Name = retParts[0]
+	retParts = retParts[1:]
+
+	if len(retParts) > 0 {
+		lastPart := retParts[len(retParts)-1]
+		if strings.HasPrefix(lastPart, "http://") || strings.HasPrefix(lastPart, "https://") {
+			tag.ExternalDocs = &TagExternalDocs{
+				URL: lastPart,
+			}
+			retParts = retParts[0 : len(retParts)-1]
+		}
+	}
+
+	tag.Description = strings.Trim(strings.Join(retParts, " "), "\"")
+
+	return
+}
+
+// BuildTags merges the tags from swagger-meta.json with the tags defined in the source (@tagdef)
+// and adds a bare tag for every tag used by a route that was never defined
+// `order` is `declared` (swagger-meta.json, then @tagdef, then undefined tags alphabetically), `alpha`,
+// or a comma separated list of tag names that come first
+func BuildTags(metaTags []Tag, tagDefs []Tag, usedTags []string, order string) (tags []Tag) {
+
+	tags = []Tag{}
+	tagIdx := map[string]int{}
+
+	for _, tag := range append(metaTags, tagDefs...) {
+
+		idx, ok := tagIdx[tag.Name]
+		if !ok {
+			tagIdx[tag.Name] = len(tags)
+			tags = append(tags, tag)
+			continue
+		}
+
+		// Definitions in swagger-meta.json win, but missing values are filled in from the source
+		if len(tags[idx].Description) == 0 {
+			tags[idx].Description = tag.Description
+		}
+
+		if tags[idx].ExternalDocs == nil {
+			tags[idx].ExternalDocs = tag.ExternalDocs
+		}
+	}
+
+	undefinedTags := []string{}
+
+	for _, name := range usedTags {
+		if _, ok := tagIdx[name]; !ok {
+			tagIdx[name] = -1
+			undefinedTags = append(undefinedTags, name)
+		}
+	}
+
+	sort.Strings(undefinedTags)
+
+	for _, name := range undefinedTags {
+		tags = append(tags, Tag{Name: name})
+	}
+
+	switch order {
+	case "", TagOrderDeclared:
+	case TagOrderAlpha:
+		sort.SliceStable(tags, func(i, j int) bool {
+			return tags[i].Name < tags[j].Name
+		})
+	default:
+		names := strings.Split(order, ",")
+		position := func(name string) int {
+			for i, orderName := range names {
+				if strings.TrimSpace(orderName) == name {
+					return i
+				}
+			}
+			return len(names)
+		}
+		sort.SliceStable(tags, func(i, j int) bool {
+			return position(tags[i].Name) < position(tags[j].Name)
+		})
+	}
+
+	return
+}
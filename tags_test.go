@@ -0,0 +1,86 @@
+package main
+
+import "testing"
+
+func TestParseTagDef(t *testing.T) {
+
+	tag, err := ParseTagDef(`users "User management" https://docs.example.com/users`)
+
+	if err != nil {
+		t.Errorf("ParseTagDef should have returned a nil error (actually '%s')", err.Error())
+	}
+
+	if tag.Name != "users" || tag.Description != "User management" {
+		t.Errorf("ParseTagDef should have returned users described as 'User management' (actually %s '%s')", tag.Name, tag.Description)
+	}
+
+	if tag.ExternalDocs == nil || tag.ExternalDocs.URL != "https://docs.example.com/users" {
+		t.Errorf("ParseTagDef should have returned external docs with the url https://docs.example.com/users")
+	}
+
+	tag, _ = ParseTagDef("orders")
+
+	if tag.Name != "orders" || tag.Description != "" || tag.ExternalDocs != nil {
+		t.Errorf("ParseTagDef should have returned a bare orders tag (actually %v)", tag)
+	}
+}
+
+func TestGetTagDefs(t *testing.T) {
+
+	lines := []string{
+		"// Package api",
+		"// @tagdef users \"User management\"",
+		"// @tagdef orders Order management",
+		"package api",
+	}
+
+	tagDefs := GetTagDefs(lines, "some/file/path")
+
+	if len(tagDefs) != 2 || tagDefs[0].Tag.Name != "users" || tagDefs[1].Tag.Description != "Order management" || tagDefs[1].LineNum != 2 {
+		t.Errorf("GetTagDefs returned unexpected tags (%v)", tagDefs)
+	}
+}
+
+func TestBuildTags(t *testing.T) {
+
+	metaTags := []Tag{
+		{Name: "users"},
+	}
+
+	tagDefs := []Tag{
+		{Name: "users", Description: "User management"},
+		{Name: "orders", Description: "Order management"},
+	}
+
+	usedTags := []string{"users", "zebras", "admin"}
+
+	tags := BuildTags(metaTags, tagDefs, usedTags, TagOrderDeclared)
+
+	expected := []string{"users", "orders", "admin", "zebras"}
+
+	if len(tags) != len(expected) {
+		t.Fatalf("BuildTags should have returned %d tags (actually %d)", len(expected), len(tags))
+	}
+
+	for i, name := range expected {
+		if tags[i].Name != name {
+			t.Errorf("BuildTags should have returned %s at index %d (actually %s)", name, i, tags[i].Name)
+		}
+	}
+
+	if tags[0].Description != "User management" {
+		t.Errorf("BuildTags should have filled in the description of the users tag (actually '%s')", tags[0].Description)
+	}
+
+	tags = BuildTags(metaTags, tagDefs, usedTags, TagOrderAlpha)
+
+	if tags[0].Name != "admin" || tags[3].Name != "zebras" {
+		t.Errorf("BuildTags should have sorted the tags alphabetically (actually %v)", tags)
+	}
+
+	tags = BuildTags(metaTags, tagDefs, usedTags, "zebras,orders")
+
+	if tags[0].Name != "zebras" || tags[1].Name != "orders" || tags[2].Name != "users" {
+		t.Errorf("BuildTags should have sorted zebras and orders first (actually %v)", tags)
+	}
+}
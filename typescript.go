@@ -0,0 +1,162 @@
+/**
+ * TypeScript
+ */
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tsFuncPattern finds the name of a function, method or arrow function declaration
+var tsFuncPattern = regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:(?:public|private|protected|static|async)\s+)*(?:function\s*\*?\s*(\w+)|(?:const|let|var)\s+(\w+)\s*(?::[^=]+)?=|(\w+)\s*(?:<[^>]*>)?\()`)
+
+// tsFieldPattern finds the name and type of an interface or class property
+// Examples: `email: string;`, `readonly id?: number`, `public tags: string[] = [];`
+var tsFieldPattern = regexp.MustCompile(`^(?:(?:public|private|protected|readonly|declare)\s+)*(\w+)(\?)?\s*:\s*([^=;]+?)\s*(=.*?)?[;,]?$`)
+
+// tsTypes are the swagger types of typescript types
+var tsTypes = map[string]string{
+	"string":  SwaggerTypeString,
+	"number":  SwaggerTypeFloat,
+	"bigint":  SwaggerTypeInt,
+	"boolean": SwaggerTypeBool,
+	"Date":    SwaggerTypeString,
+	"any":     "object",
+	"unknown": "object",
+	"object":  "object",
+	"Record":  "object",
+	"Map":     "object",
+}
+
+// TypeScriptLanguage reads annotations from typescript files, with models declared as interfaces, type literals or classes
+// Example:
+//
+//	// @model User
+//	export interface User {
+//	  // The user's email
+//	  // @format email
+//	  email: string;
+//	  nickname?: string;
+//	  tags: string[];
+//	}
+type TypeScriptLanguage struct{}
+
+// Name returns `typescript`
+func (TypeScriptLanguage) Name() string {
+	return "typescript"
+}
+
+// Extensions returns the extensions of typescript files
+func (TypeScriptLanguage) Extensions() []string {
+	return []string{".ts", ".tsx"}
+}
+
+// CommentSyntax returns the `//` and `/* */` comments of typescript
+func (TypeScriptLanguage) CommentSyntax() CommentSyntax {
+	return CommentSyntax{LineComments: []string{"//"}, BlockStart: "/*", BlockEnd: "*/"}
+}
+
+// FuncName returns the name of the function declared after the comment block, skipping decorators (e.g. `@Get(':id')`)
+func (TypeScriptLanguage) FuncName(lines []string, blockStart int, blockEnd int) string {
+
+	for lineNum := blockEnd + 1; lineNum < len(lines); lineNum++ {
+
+		line := strings.TrimSpace(lines[lineNum])
+		if strings.HasPrefix(line, "@") {
+			continue
+		}
+
+		matches := tsFuncPattern.FindStringSubmatch(line)
+		if matches == nil {
+			return ""
+		}
+		return matches[1] + matches[2] + matches[3]
+	}
+
+	return ""
+}
+
+// ModelFields returns the properties of the interface, type or class declared after the comment block
+// Properties are required unless they are optional (`name?: type`) or nullable (`type | null`)
+func (TypeScriptLanguage) ModelFields(lines []string, commentLines []CommentLine, blockStart int, blockEnd int) []ModelField {
+	return classFields(lines, commentLines, blockEnd+1, func(line string, annotations []string, comments []string) (ModelField, bool) {
+		return ParseTypeScriptField(line)
+	})
+}
+
+// ParseTypeScriptField parses a property declaration, along with its trailing comment
+// Returns false if the line is not a property (e.g. a method)
+func ParseTypeScriptField(line string) (field ModelField, ok bool) {
+
+	line, field.Description = splitTrailingComment(line, "//")
+
+	matches := tsFieldPattern.FindStringSubmatch(line)
+	if matches == nil || strings.ContainsAny(matches[3], "({") {
+		return
+	}
+
+	field.Name = matches[1]
+	field.Required = len(matches[2]) == 0 && len(matches[4]) == 0
+
+	typeName := strings.TrimSpace(matches[3])
+	typeParts := []string{}
+
+	for _, typePart := range strings.Split(typeName, "|") {
+		typePart = strings.TrimSpace(typePart)
+		if typePart == "null" || typePart == "undefined" {
+			field.Required = false
+			continue
+		}
+		typeParts = append(typeParts, typePart)
+	}
+
+	if len(typeParts) == 0 {
+		return
+	}
+
+	typeName = typeParts[0]
+
+	if len(typeParts) > 1 {
+		// Unions of string literals (e.g. `'admin' | 'user'`) are strings, other unions have no single swagger type
+		typeName = "object"
+		if isStringLiteral(typeParts...) {
+			typeName = "string"
+		}
+	}
+
+	isArray := false
+
+	switch genericName, args := splitTypeArgs(typeName, "<", ">"); {
+	case strings.HasSuffix(typeName, "[]"):
+		typeName = strings.TrimSuffix(typeName, "[]")
+		isArray = true
+	case (genericName == "Array" || genericName == "ReadonlyArray" || genericName == "Set") && len(args) == 1:
+		typeName = args[0]
+		isArray = true
+	case len(args) > 0:
+		typeName = genericName
+	}
+
+	swaggerType := tsTypes[typeName]
+	if isStringLiteral(typeName) {
+		swaggerType = SwaggerTypeString
+	}
+
+	setFieldType(&field, swaggerType, typeName, isArray)
+
+	ok = true
+	return
+}
+
+// isStringLiteral checks if types are string literals (e.g. `'admin'`)
+func isStringLiteral(typeNames ...string) bool {
+
+	for _, typeName := range typeNames {
+		if !strings.HasPrefix(typeName, "'") && !strings.HasPrefix(typeName, "\"") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseTypeScriptField(t *testing.T) {
+
+	expected := map[string]ModelField{
+		"email: string;":                       {Name: "email", Type: "string", Required: true},
+		"readonly id?: number":                 {Name: "id", Type: "number"},
+		"tags: Array<string>; // The tags":     {Name: "tags", Type: "array", ItemsType: "string", Required: true, Description: "The tags"},
+		"friends: User[] | null;":              {Name: "friends", Type: "array", Ref: "User"},
+		"owner: models.User;":                  {Name: "owner", Type: "#object", Ref: "models.User", Required: true},
+		"role: 'admin' | 'user';":              {Name: "role", Type: "string", Required: true},
+		"public createdAt: Date = new Date();": {Name: "createdAt", Type: "string"},
+	}
+
+	for line, expectedField := range expected {
+		field, ok := ParseTypeScriptField(line)
+		if !ok || field.Name != expectedField.Name || field.Type != expectedField.Type || field.ItemsType != expectedField.ItemsType || field.Ref != expectedField.Ref || field.Required != expectedField.Required || field.Description != expectedField.Description {
+			t.Errorf("ParseTypeScriptField should have returned %+v for '%s' (actually %+v)", expectedField, line, field)
+		}
+	}
+
+	for _, line := range []string{"getName(): string {", "constructor(private api: Api) {}"} {
+		if _, ok := ParseTypeScriptField(line); ok {
+			t.Errorf("ParseTypeScriptField should not have returned a field for '%s'", line)
+		}
+	}
+}
+
+func TestParseLines_TypeScript(t *testing.T) {
+
+	lines := []string{
+		"/**",
+		" * A user",
+		" * @model User",
+		" */",
+		"export interface User {",
+		"  // The email",
+		"  // @format email",
+		"  email: string;",
+		"  address?: {",
+		"    city: string;",
+		"  };",
+		"  nickname?: string;",
+		"}",
+		"",
+		"export class UsersController {",
+		"  /**",
+		"   * @route GetUser GET /users/{id}",
+		"   * @return 200 User",
+		"   */",
+		"  @Get(':id')",
+		"  async getUser(@Param('id') id: string): Promise<User> {",
+		"  }",
+		"}",
+	}
+
+	result := ParseLines(lines, "src/users.ts", false)
+
+	model := result.Models["User"]
+	if model.Description != "A user" || len(model.Fields) != 2 {
+		t.Fatalf("ParseLines should have returned the model User with 2 fields (actually %+v)", model)
+	}
+
+	if model.Fields[0].Name != "email" || model.Fields[0].Description != "The email" || model.Fields[0].Tags[TagFormat][0] != "email" {
+		t.Errorf("ParseLines should have returned the field email with its comment (actually %+v)", model.Fields[0])
+	}
+
+	if model.Fields[1].Name != "nickname" || model.Fields[1].Required {
+		t.Errorf("ParseLines should have returned the optional field nickname (actually %+v)", model.Fields[1])
+	}
+
+	if routes := result.Routes["/users/{id}"]; len(routes) != 1 || routes[0].Handler != "getUser" {
+		t.Errorf("ParseLines should have returned the route GetUser handled by getUser (actually %+v)", result.Routes)
+	}
+}
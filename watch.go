@@ -0,0 +1,210 @@
+/**
+ * Watch
+ */
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileStamp is what a watched file looked like when it was last checked
+type FileStamp struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// Watcher polls the source files of a swagger spec and rebuilds it when they change
+type Watcher struct {
+	Interval time.Duration // time between two polls
+	Debounce time.Duration // a burst of changes is rebuilt once no file has changed for this long
+	Snapshot func() (map[string]FileStamp, error)
+	Rebuild  func(changed []string)
+
+	// Referenced, if set, stats the files the last rebuild referenced (e.g. with `file:`), which are watched from then on
+	Referenced func() map[string]FileStamp
+}
+
+// Run rebuilds the spec, then polls the files and rebuilds it after every burst of changes until `stop` is closed
+func (w *Watcher) Run(stop <-chan struct{}) {
+
+	previous, err := w.Snapshot()
+	if err != nil {
+		log.Printf("Watch Error: %s", err.Error())
+	}
+
+	w.Rebuild(nil)
+	previous = w.watchReferenced(previous)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	pending := []string{}
+	lastChange := time.Time{}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := w.Snapshot()
+		if err != nil {
+			log.Printf("Watch Error: %s", err.Error())
+			continue
+		}
+
+		if changed := ChangedFiles(previous, current); len(changed) > 0 {
+			pending = mergeChanged(pending, changed)
+			lastChange = time.Now()
+			previous = current
+			continue
+		}
+
+		if len(pending) > 0 && time.Since(lastChange) >= w.Debounce {
+			w.Rebuild(pending)
+			previous = w.watchReferenced(previous)
+			pending = []string{}
+		}
+	}
+}
+
+// watchReferenced adds the files newly referenced by the last rebuild to `previous`, so they are not reported as changed
+func (w *Watcher) watchReferenced(previous map[string]FileStamp) map[string]FileStamp {
+
+	if previous == nil {
+		previous = map[string]FileStamp{}
+	}
+
+	if w.Referenced == nil {
+		return previous
+	}
+
+	for filePath, stamp := range w.Referenced() {
+		if _, ok := previous[filePath]; !ok {
+			previous[filePath] = stamp
+		}
+	}
+
+	return previous
+}
+
+// SnapshotFiles stats `filePaths`. Missing files are left out
+func SnapshotFiles(filePaths []string) (stamps map[string]FileStamp) {
+
+	stamps = map[string]FileStamp{}
+
+	for _, filePath := range filePaths {
+		fileInfo, err := os.Stat(filePath)
+		if err != nil {
+			continue
+		}
+		stamps[filePath] = FileStamp{fileInfo.ModTime(), fileInfo.Size()}
+	}
+
+	return
+}
+
+// ChangedFiles returns the (sorted) paths of the files that were added, removed or modified between two snapshots
+func ChangedFiles(previous map[string]FileStamp, current map[string]FileStamp) (changed []string) {
+
+	for filePath, stamp := range current {
+		if previousStamp, ok := previous[filePath]; !ok || !previousStamp.ModTime.Equal(stamp.ModTime) || previousStamp.Size != stamp.Size {
+			changed = append(changed, filePath)
+		}
+	}
+
+	for filePath := range previous {
+		if _, ok := current[filePath]; !ok {
+			changed = append(changed, filePath)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return
+}
+
+// mergeChanged adds the paths in `changed` that are not in `pending` yet
+func mergeChanged(pending []string, changed []string) []string {
+
+	for _, filePath := range changed {
+		if !inArray(filePath, pending) {
+			pending = append(pending, filePath)
+		}
+	}
+
+	sort.Strings(pending)
+
+	return pending
+}
+
+// describeChanged formats the changed files for log messages
+func describeChanged(changed []string) string {
+
+	if len(changed) > 5 {
+		return fmt.Sprintf("%s and %d more", strings.Join(changed[:5], ", "), len(changed)-5)
+	}
+
+	return strings.Join(changed, ", ")
+}
+
+// Watch builds the swagger spec from `rootPaths` and writes it to `outputs`, then rebuilds it whenever a source file or
+// the meta file changes, along with the files they reference (descriptions and examples). Only changed files are parsed again, and errors are logged without stopping the watch
+func (s *Swaggerf) Watch(rootPaths []string, metaPath string, outputs []Output, interval time.Duration, debounce time.Duration) {
+
+	if len(s.CachePath) > 0 {
+		s.cache = ReadCache(s.CachePath)
+	} else {
+		s.cache = NewCache()
+	}
+
+	watcher := Watcher{
+		Interval: interval,
+		Debounce: debounce,
+		Snapshot: func() (map[string]FileStamp, error) {
+			files, err := s.SourceFiles(rootPaths)
+			if err != nil {
+				return nil, err
+			}
+			return SnapshotFiles(append(append(files, s.referencedFiles...), metaPath)), nil
+		},
+		Referenced: func() map[string]FileStamp {
+			return SnapshotFiles(s.referencedFiles)
+		},
+		Rebuild: func(changed []string) {
+			if len(changed) > 0 {
+				log.Printf("Changed: %s", describeChanged(changed))
+			}
+			if err := s.rebuild(rootPaths, metaPath, outputs); err != nil {
+				log.Printf("Build Error: %s", err.Error())
+			}
+		},
+	}
+
+	log.Printf("Watching %s for changes", strings.Join(rootPaths, ", "))
+	watcher.Run(nil)
+}
+
+// rebuild reads the meta file, then builds and writes the swagger spec
+func (s *Swaggerf) rebuild(rootPaths []string, metaPath string, outputs []Output) error {
+
+	jsonBytes, err := ReadJSONToBytes(metaPath)
+	if err != nil {
+		return err
+	}
+
+	s.Swagger = Swagger{}
+	s.ParseSwaggerConfig(jsonBytes)
+
+	if err = s.BuildSwagger(rootPaths...); err != nil {
+		return err
+	}
+
+	return WriteOutputs(s.Spec(), outputs)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChangedFiles(t *testing.T) {
+
+	now := time.Now()
+	previous := map[string]FileStamp{
+		"a.go": {now, 10},
+		"b.go": {now, 10},
+		"c.go": {now, 10},
+	}
+	current := map[string]FileStamp{
+		"a.go": {now, 10},
+		"b.go": {now.Add(time.Second), 10},
+		"d.go": {now, 10},
+	}
+
+	if changed := strings.Join(ChangedFiles(previous, current), ","); changed != "b.go,c.go,d.go" {
+		t.Errorf("ChangedFiles should have returned b.go,c.go,d.go (actually %s)", changed)
+	}
+}
+
+func TestWatcherWatchReferenced(t *testing.T) {
+
+	stamp := FileStamp{time.Now(), 1}
+	watcher := Watcher{
+		Referenced: func() map[string]FileStamp {
+			return map[string]FileStamp{"a.go": {time.Now(), 2}, "docs/a.md": stamp}
+		},
+	}
+
+	previous := watcher.watchReferenced(map[string]FileStamp{"a.go": stamp})
+
+	if len(previous) != 2 || previous["a.go"].Size != 1 || previous["docs/a.md"] != stamp {
+		t.Errorf("Watcher.watchReferenced should have only added docs/a.md (actually %v)", previous)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+
+	mutex := sync.Mutex{}
+	stamps := map[string]FileStamp{"a.go": {time.Now(), 1}}
+	rebuilds := make(chan []string, 10)
+	stop := make(chan struct{})
+
+	watcher := Watcher{
+		Interval: 5 * time.Millisecond,
+		Debounce: 50 * time.Millisecond,
+		Snapshot: func() (map[string]FileStamp, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			snapshot := map[string]FileStamp{}
+			for filePath, stamp := range stamps {
+				snapshot[filePath] = stamp
+			}
+			return snapshot, nil
+		},
+		Rebuild: func(changed []string) {
+			rebuilds <- changed
+		},
+	}
+
+	go watcher.Run(stop)
+	defer close(stop)
+
+	if changed := <-rebuilds; changed != nil {
+		t.Errorf("Watcher.Run should have started with a full build (actually %v)", changed)
+	}
+
+	// A burst of changes is rebuilt once
+	for _, filePath := range []string{"a.go", "b.go"} {
+		mutex.Lock()
+		stamps[filePath] = FileStamp{time.Now(), 2}
+		mutex.Unlock()
+		time.Sleep(15 * time.Millisecond)
+	}
+
+	select {
+	case changed := <-rebuilds:
+		if strings.Join(changed, ",") != "a.go,b.go" {
+			t.Errorf("Watcher.Run should have rebuilt a.go,b.go (actually %v)", changed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Watcher.Run should have rebuilt after the changes")
+	}
+
+	select {
+	case changed := <-rebuilds:
+		t.Errorf("Watcher.Run should have rebuilt only once (actually also %v)", changed)
+	case <-time.After(100 * time.Millisecond):
+	}
+}